@@ -1,11 +1,13 @@
 import { useState, useEffect, useCallback, useRef } from 'react';
 import NicknameModal from '../../common/NicknameModal';
 import useHighScore from '../../../hooks/useHighScore';
+import { useServerGameSession, createMulberry32 } from '../../../hooks/useGameSession';
 import './JumpRunner.css';
 
 const GAME_WIDTH = 1200;
 const GAME_HEIGHT = 600;
 const GROUND_HEIGHT = 40;
+const PLAYER_X = 50;
 const PLAYER_WIDTH = 70;
 const PLAYER_HEIGHT = 70;
 const OBSTACLE_WIDTH = 60;
@@ -15,6 +17,33 @@ const JUMP_FORCE = 18;
 const INITIAL_SPEED = 6;
 const SPEED_INCREMENT = 0.002;
 
+// 고정 프레임 간격 (서버 시뮬레이션과 동일한 60fps)
+const FRAME_MS = 1000 / 60;
+// 탭 전환 등으로 밀린 시간은 최대 5프레임까지만 따라잡음
+const MAX_CATCH_UP_FRAMES = 5;
+
+// 충돌 판정 (서버 jumpRunnerCollides와 동일)
+function collides(playerY, obstacles) {
+  const playerLeft = PLAYER_X;
+  const playerRight = playerLeft + PLAYER_WIDTH - 10;
+  const playerBottom = GROUND_HEIGHT + playerY;
+  const playerTop = playerBottom + PLAYER_HEIGHT;
+
+  return obstacles.some(obs => {
+    const obsLeft = obs.x + 5;
+    const obsRight = obs.x + OBSTACLE_WIDTH - 5;
+    const obsBottom = GROUND_HEIGHT;
+    const obsTop = GROUND_HEIGHT + obs.height;
+
+    return (
+      playerRight > obsLeft &&
+      playerLeft < obsRight &&
+      playerBottom < obsTop &&
+      playerTop > obsBottom
+    );
+  });
+}
+
 function JumpRunner() {
   const [gameState, setGameState] = useState('ready'); // ready, playing, gameover
   const [score, setScore] = useState(0);
@@ -23,20 +52,37 @@ function JumpRunner() {
   const [obstacles, setObstacles] = useState([]);
   const [speed, setSpeed] = useState(INITIAL_SPEED);
   const [showNicknameModal, setShowNicknameModal] = useState(false);
+  const [result, setResult] = useState(null); // 서버가 검증한 결과
   
+  const playerYRef = useRef(0);
   const playerVelocityRef = useRef(0);
   const isJumpingRef = useRef(false);
+  const jumpQueuedRef = useRef(false);
+  const obstaclesRef = useRef([]);
+  const obstacleIdRef = useRef(0);
   const gameLoopRef = useRef(null);
   const obstacleTimerRef = useRef(0);
   const lastTimeRef = useRef(0);
+  const accumulatorRef = useRef(0);
   const speedRef = useRef(INITIAL_SPEED);
-  const scoreRef = useRef(0);
+  const frameRef = useRef(0);
+  const rngRef = useRef(null);
+  const jumpsRef = useRef([]); // 점프가 적용된 프레임 번호
+  const startingRef = useRef(false);
+
+  // 서버 세션 훅
+  const {
+    sessionId,
+    startSession,
+    finishSession,
+    submitScore,
+    resetSession,
+  } = useServerGameSession('jump-runner');
 
-  // Jump handler
+  // Jump handler (다음 프레임에 적용)
   const jump = useCallback(() => {
-    if (!isJumpingRef.current && gameState === 'playing') {
-      playerVelocityRef.current = JUMP_FORCE;
-      isJumpingRef.current = true;
+    if (gameState === 'playing') {
+      jumpQueuedRef.current = true;
     }
   }, [gameState]);
 
@@ -59,127 +105,171 @@ function JumpRunner() {
     return () => window.removeEventListener('keydown', handleKeyDown);
   }, [gameState, jump]);
 
-  // Start game
-  const startGame = () => {
-    setGameState('playing');
-    setScore(0);
-    setPlayerY(0);
-    setObstacles([]);
-    setSpeed(INITIAL_SPEED);
+  // Start game (장애물은 서버 시드로 생성)
+  const startGame = async () => {
+    if (startingRef.current) return;
+    startingRef.current = true;
+
+    let data;
+    try {
+      data = await startSession();
+    } catch (err) {
+      console.error('Failed to start game:', err);
+      alert('게임 시작에 실패했습니다. 다시 시도해주세요.');
+      return;
+    } finally {
+      startingRef.current = false;
+    }
+
+    rngRef.current = createMulberry32(data.seed);
+    playerYRef.current = 0;
     playerVelocityRef.current = 0;
     isJumpingRef.current = false;
+    jumpQueuedRef.current = false;
+    obstaclesRef.current = [];
     obstacleTimerRef.current = 0;
     lastTimeRef.current = 0;
+    accumulatorRef.current = 0;
     speedRef.current = INITIAL_SPEED;
-    scoreRef.current = 0;
+    frameRef.current = 0;
+    jumpsRef.current = [];
+    setScore(0);
+    setPlayerY(0);
+    setObstacles([]);
+    setSpeed(INITIAL_SPEED);
+    setResult(null);
+    setGameState('playing');
   };
 
   // Reset game
   const resetGame = () => {
+    resetSession();
     setGameState('ready');
     setScore(0);
     setPlayerY(0);
     setObstacles([]);
     setSpeed(INITIAL_SPEED);
+    setResult(null);
     lastTimeRef.current = 0;
     speedRef.current = INITIAL_SPEED;
-    scoreRef.current = 0;
   };
 
+  // 게임 종료: 점프 기록을 서버에서 재생해 점수 확정
+  const finishGame = useCallback(async () => {
+    setGameState('gameover');
+    try {
+      const verified = await finishSession({ jumps: jumpsRef.current, frames: frameRef.current });
+      setResult(verified);
+      if (!verified.canSubmit && verified.message) {
+        console.warn('JumpRunner run rejected:', verified.message);
+      }
+    } catch (err) {
+      console.error('Failed to finish game:', err);
+      setResult({ canSubmit: false });
+    }
+  }, [finishSession]);
+
   // Game loop
   useEffect(() => {
     if (gameState !== 'playing') return;
 
-    const gameLoop = (currentTime) => {
-      // Delta time 계산 (60fps 기준 정규화)
-      if (lastTimeRef.current === 0) {
-        lastTimeRef.current = currentTime;
+    // 한 프레임 진행 (서버 SimulateJumpRunner와 같은 순서), 충돌하면 true
+    const stepFrame = () => {
+      // 점프 입력 (공중에서는 무시)
+      if (jumpQueuedRef.current) {
+        jumpQueuedRef.current = false;
+        if (!isJumpingRef.current) {
+          playerVelocityRef.current = JUMP_FORCE;
+          isJumpingRef.current = true;
+          jumpsRef.current.push(frameRef.current);
+        }
       }
-      const deltaTime = Math.min((currentTime - lastTimeRef.current) / 16.67, 3); // 최대 3프레임으로 제한
-      lastTimeRef.current = currentTime;
 
-      // Update player position (gravity and jump)
-      setPlayerY(prev => {
-        let newVelocity = playerVelocityRef.current - (GRAVITY * deltaTime);
-        playerVelocityRef.current = newVelocity;
-        
-        let newY = prev + (newVelocity * deltaTime);
-        
-        // 땅에 닿으면 멈춤
-        if (newY <= 0) {
-          newY = 0;
-          playerVelocityRef.current = 0;
-          isJumpingRef.current = false;
-        }
-        return newY;
-      });
-
-      // Update obstacles
-      setObstacles(prev => {
-        const updated = prev
-          .map(obs => ({ ...obs, x: obs.x - (speedRef.current * deltaTime) }))
-          .filter(obs => obs.x > -OBSTACLE_WIDTH);
-        return updated;
-      });
-
-      // Spawn new obstacles
-      obstacleTimerRef.current += deltaTime;
+      // 중력 및 위치 갱신
+      playerVelocityRef.current -= GRAVITY;
+      playerYRef.current += playerVelocityRef.current;
+      // 땅에 닿으면 멈춤
+      if (playerYRef.current <= 0) {
+        playerYRef.current = 0;
+        playerVelocityRef.current = 0;
+        isJumpingRef.current = false;
+      }
+
+      // 장애물 이동
+      obstaclesRef.current = obstaclesRef.current
+        .map(obs => ({ ...obs, x: obs.x - speedRef.current }))
+        .filter(obs => obs.x > -OBSTACLE_WIDTH);
+
+      // 장애물 생성 (시드 기반)
+      obstacleTimerRef.current += 1;
       if (obstacleTimerRef.current > 100 - Math.min(speedRef.current * 5, 50)) {
-        if (Math.random() < 0.02 + speedRef.current * 0.005) {
-          setObstacles(prev => [...prev, { 
-            x: GAME_WIDTH, 
-            id: Date.now(),
-            height: OBSTACLE_HEIGHT + Math.random() * 15
-          }]);
+        if (rngRef.current() < 0.02 + speedRef.current * 0.005) {
+          obstaclesRef.current.push({
+            x: GAME_WIDTH,
+            id: obstacleIdRef.current++,
+            height: OBSTACLE_HEIGHT + rngRef.current() * 15
+          });
           obstacleTimerRef.current = 0;
         }
       }
 
       // Increase speed over time
-      speedRef.current += SPEED_INCREMENT * deltaTime;
+      speedRef.current += SPEED_INCREMENT;
+      frameRef.current += 1;
+
+      return collides(playerYRef.current, obstaclesRef.current);
+    };
+
+    const render = () => {
+      setPlayerY(playerYRef.current);
+      setObstacles(obstaclesRef.current);
       setSpeed(speedRef.current);
+      setScore(frameRef.current);
+    };
+
+    const gameLoop = (currentTime) => {
+      if (lastTimeRef.current === 0) {
+        lastTimeRef.current = currentTime;
+      }
+      accumulatorRef.current = Math.min(
+        accumulatorRef.current + (currentTime - lastTimeRef.current),
+        FRAME_MS * MAX_CATCH_UP_FRAMES
+      );
+      lastTimeRef.current = currentTime;
 
-      // Update score
-      scoreRef.current += deltaTime;
-      setScore(Math.floor(scoreRef.current));
+      // 실제 경과 시간만큼 고정 간격 프레임 진행
+      while (accumulatorRef.current >= FRAME_MS) {
+        accumulatorRef.current -= FRAME_MS;
+        if (stepFrame()) {
+          render();
+          finishGame();
+          return;
+        }
+      }
 
+      render();
       gameLoopRef.current = requestAnimationFrame(gameLoop);
     };
 
     gameLoopRef.current = requestAnimationFrame(gameLoop);
     return () => cancelAnimationFrame(gameLoopRef.current);
-  }, [gameState]);
+  }, [gameState, finishGame]);
 
-  // Collision detection
+  // 서버 검증이 끝나면 최고 점수 체크
   useEffect(() => {
-    if (gameState !== 'playing') return;
+    if (gameState === 'gameover' && result?.canSubmit && checkAndUpdateHighScore(result.finalScore)) {
+      setShowNicknameModal(true);
+    }
+  }, [gameState, result, checkAndUpdateHighScore]);
 
-    const playerLeft = 50;
-    const playerRight = playerLeft + PLAYER_WIDTH - 10;
-    const playerBottom = GROUND_HEIGHT + playerY;
-    const playerTop = playerBottom + PLAYER_HEIGHT;
-
-    for (const obs of obstacles) {
-      const obsLeft = obs.x + 5;
-      const obsRight = obs.x + OBSTACLE_WIDTH - 5;
-      const obsBottom = GROUND_HEIGHT;
-      const obsTop = GROUND_HEIGHT + obs.height;
-
-      if (
-        playerRight > obsLeft &&
-        playerLeft < obsRight &&
-        playerBottom < obsTop &&
-        playerTop > obsBottom
-      ) {
-        // Collision detected
-        setGameState('gameover');
-        if (checkAndUpdateHighScore(score)) {
-          setShowNicknameModal(true);
-        }
-        break;
-      }
+  // 닉네임 제출 핸들러
+  const handleNicknameSubmit = async (nickname) => {
+    const submitted = await submitScore(nickname);
+    if (!submitted.success) {
+      throw new Error('Score submission failed');
     }
-  }, [obstacles, playerY, gameState, score, checkAndUpdateHighScore]);
+    setShowNicknameModal(false);
+  };
 
   // Calculate speed level (every 2 speed increase = 1 level)
   const speedLevel = Math.floor((speed - INITIAL_SPEED) / 2) + 1;
@@ -221,7 +311,7 @@ function JumpRunner() {
         <div 
           className={`player ${isJumpingRef.current ? 'jumping' : ''}`}
           style={{
-            left: 50,
+            left: PLAYER_X,
             bottom: GROUND_HEIGHT + playerY,
             width: PLAYER_WIDTH,
             height: PLAYER_HEIGHT
@@ -277,6 +367,9 @@ function JumpRunner() {
           <div className="game-overlay gameover">
             <h2 className="pixel-font">GAME OVER</h2>
             <p>점수: {formatScore(score)}</p>
+            {result && !result.canSubmit && result.message && (
+              <p className="hint">기록이 인정되지 않았습니다: {result.message}</p>
+            )}
             <p className="hint">SPACE 또는 클릭으로 재시작</p>
           </div>
         )}
@@ -289,10 +382,11 @@ function JumpRunner() {
       {/* Nickname modal */}
       {showNicknameModal && (
         <NicknameModal
-          score={score}
+          score={result.finalScore}
           gameName="jump-runner"
           formatScore={formatScore}
-          onSubmit={() => setShowNicknameModal(false)}
+          sessionId={sessionId}
+          onSubmit={handleNicknameSubmit}
           onClose={() => setShowNicknameModal(false)}
         />
       )}
@@ -1,9 +1,10 @@
 import { useState, useEffect, useCallback, useRef } from 'react';
 import NicknameModal from '../../common/NicknameModal';
 import useHighScore from '../../../hooks/useHighScore';
+import { useServerGameSession } from '../../../hooks/useGameSession';
 import './MemoryCard.css';
 
-// 이모지 카드 세트
+// 이모지 카드 세트 (서버가 알려주는 그림 인덱스 순서)
 const CARD_EMOJIS = [
   '🍎', '🍊', '🍋', '🍇', '🍓', '🍒',
   '🐶', '🐱', '🐼', '🦊', '🐰', '🐻',
@@ -56,47 +57,50 @@ function MemoryCard() {
   const [isChecking, setIsChecking] = useState(false);
 
   const timerRef = useRef(null);
+  // 뒤집기 요청은 서버에서 순서대로 처리되어야 하므로 한 번에 하나씩
+  const flippingRef = useRef(false);
 
-  // 카드 셔플
-  const shuffleCards = useCallback((pairCount) => {
-    const selectedEmojis = CARD_EMOJIS.slice(0, pairCount);
-    const cardPairs = [...selectedEmojis, ...selectedEmojis];
-    
-    // Fisher-Yates 셔플
-    for (let i = cardPairs.length - 1; i > 0; i--) {
-      const j = Math.floor(Math.random() * (i + 1));
-      [cardPairs[i], cardPairs[j]] = [cardPairs[j], cardPairs[i]];
-    }
-
-    return cardPairs.map((emoji, index) => ({
-      id: index,
-      emoji,
-      isFlipped: false,
-      isMatched: false
-    }));
-  }, []);
+  // 서버 세션 훅 (덱은 서버만 알고 있음)
+  const {
+    sessionId,
+    startSession,
+    sendEvent,
+    finishSession,
+    submitScore,
+    resetSession,
+  } = useServerGameSession('memory-card');
 
   // 게임 시작
-  const startGame = useCallback((selectedLevel) => {
-    const config = LEVEL_CONFIG[selectedLevel];
+  const startGame = useCallback(async (selectedLevel) => {
+    let data;
+    try {
+      data = await startSession({ level: selectedLevel });
+    } catch (err) {
+      console.error('Failed to start game:', err);
+      alert('게임 시작에 실패했습니다. 다시 시도해주세요.');
+      return;
+    }
+
     setLevel(selectedLevel);
-    setCards(shuffleCards(config.pairs));
+    setCards(data.cards.map(id => ({ id, emoji: null })));
     setFlipped([]);
     setMatched([]);
     setMoves(0);
-    setTimeLeft(config.timeLimit);
+    setTimeLeft(data.timeLimit);
     setScore(0);
     setIsChecking(false);
+    flippingRef.current = false;
     setGameState('playing');
-  }, [shuffleCards]);
+  }, [startSession]);
 
   // 게임 리셋
   const resetGame = () => {
     clearInterval(timerRef.current);
+    resetSession();
     setGameState('levelSelect');
   };
 
-  // 타이머
+  // 타이머 (표시용, 시간 초과 판정은 서버가 함)
   useEffect(() => {
     if (gameState !== 'playing') return;
 
@@ -113,29 +117,62 @@ function MemoryCard() {
     return () => clearInterval(timerRef.current);
   }, [gameState]);
 
-  // 카드 클릭 처리
-  const handleCardClick = (index) => {
+  // 시간 초과 시 세션 종료
+  useEffect(() => {
+    if (gameState !== 'gameover') return;
+    finishSession().catch(err => console.error('Failed to finish game:', err));
+  }, [gameState, finishSession]);
+
+  // 카드 클릭 처리: 서버가 그림을 공개하고 짝을 판정
+  const handleCardClick = async (index) => {
     if (gameState !== 'playing') return;
-    if (isChecking) return;
+    if (isChecking || flippingRef.current) return;
     if (flipped.includes(index)) return;
     if (matched.includes(index)) return;
     if (flipped.length >= 2) return;
 
+    flippingRef.current = true;
+    let result;
+    try {
+      result = await sendEvent('flip', { cardId: cards[index].id });
+    } catch (err) {
+      console.error('Failed to flip card:', err);
+      return;
+    } finally {
+      flippingRef.current = false;
+    }
+
+    if (!result.valid) {
+      if (result.gameOver) {
+        setGameState('gameover');
+      }
+      return;
+    }
+
+    setCards(prev => prev.map((card, i) => (
+      i === index ? { ...card, emoji: CARD_EMOJIS[result.face] } : card
+    )));
+
     const newFlipped = [...flipped, index];
     setFlipped(newFlipped);
 
     if (newFlipped.length === 2) {
-      setMoves(m => m + 1);
+      setMoves(result.moves);
       setIsChecking(true);
 
       const [first, second] = newFlipped;
       
-      if (cards[first].emoji === cards[second].emoji) {
+      if (result.matched) {
         // 매칭 성공
         setTimeout(() => {
           setMatched(prev => [...prev, first, second]);
           setFlipped([]);
           setIsChecking(false);
+          if (result.won) {
+            clearInterval(timerRef.current);
+            setScore(result.finalScore);
+            setGameState('win');
+          }
         }, 500);
       } else {
         // 매칭 실패
@@ -147,21 +184,6 @@ function MemoryCard() {
     }
   };
 
-  // 승리 체크
-  useEffect(() => {
-    if (gameState !== 'playing' || !level) return;
-
-    const config = LEVEL_CONFIG[level];
-    if (matched.length === config.pairs * 2) {
-      clearInterval(timerRef.current);
-      
-      // 점수 계산: (남은 시간 × 10) + 레벨 보너스 - (이동 횟수 × 2)
-      const finalScore = Math.max(0, (timeLeft * 10) + config.bonus - (moves * 2));
-      setScore(finalScore);
-      setGameState('win');
-    }
-  }, [matched, gameState, level, timeLeft, moves]);
-
   // 게임 종료 시 최고 점수 체크
   useEffect(() => {
     if ((gameState === 'win' || gameState === 'gameover') && score > 0 && checkAndUpdateHighScore(score)) {
@@ -169,6 +191,15 @@ function MemoryCard() {
     }
   }, [gameState, score, checkAndUpdateHighScore]);
 
+  // 닉네임 제출 핸들러
+  const handleNicknameSubmit = async (nickname) => {
+    const submitted = await submitScore(nickname);
+    if (!submitted.success) {
+      throw new Error('Score submission failed');
+    }
+    setShowNicknameModal(false);
+  };
+
   // 시간 포맷
   const formatTime = (seconds) => {
     const mins = Math.floor(seconds / 60);
@@ -301,7 +332,7 @@ function MemoryCard() {
 
       <div className="game-instructions">
         <p><strong>규칙:</strong> 같은 그림의 카드 2장을 찾아 짝을 맞추세요!</p>
-        <p><strong>점수:</strong> (남은 시간 × 10) + 레벨 보너스 - (이동 횟수 × 2)</p>
+        <p><strong>점수:</strong> (남은 시간 × 10) + 레벨 보너스 - (이동 횟수 × 2) + 연속 매칭 보너스</p>
       </div>
 
       {/* 닉네임 모달 */}
@@ -309,7 +340,8 @@ function MemoryCard() {
         <NicknameModal
           score={score}
           gameName="memory-card"
-          onSubmit={() => setShowNicknameModal(false)}
+          sessionId={sessionId}
+          onSubmit={handleNicknameSubmit}
           onClose={() => setShowNicknameModal(false)}
         />
       )}
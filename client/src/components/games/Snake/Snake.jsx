@@ -1,6 +1,7 @@
 import { useState, useEffect, useCallback, useRef } from 'react';
 import NicknameModal from '../../common/NicknameModal';
 import useHighScore from '../../../hooks/useHighScore';
+import { useServerGameSession, createMulberry32 } from '../../../hooks/useGameSession';
 import './Snake.css';
 
 const GRID_SIZE = 20;
@@ -29,54 +30,102 @@ const OPPOSITE = {
   RIGHT: 'LEFT'
 };
 
+// 서버 이동 기록의 방향 코드
+const DIRECTION_CODES = {
+  UP: 'U',
+  DOWN: 'D',
+  LEFT: 'L',
+  RIGHT: 'R'
+};
+
+// 시드 기반 음식 생성 (서버 generateSnakeFood와 같은 순서로 난수 사용)
+function generateFood(rng, currentSnake) {
+  let newFood;
+  do {
+    newFood = {
+      x: Math.floor(rng() * GRID_SIZE),
+      y: Math.floor(rng() * GRID_SIZE)
+    };
+  } while (currentSnake.some(segment => segment.x === newFood.x && segment.y === newFood.y));
+  return newFood;
+}
+
 function Snake() {
   const [gameState, setGameState] = useState('ready'); // ready, levelSelect, playing, gameover
   const [level, setLevel] = useState(null);
   const [snake, setSnake] = useState([{ x: 10, y: 10 }]);
-  const [direction, setDirection] = useState('RIGHT');
   const [food, setFood] = useState({ x: 15, y: 10 });
   const [score, setScore] = useState(0);
   const [highScore, , checkAndUpdateHighScore] = useHighScore('snake');
   const [showNicknameModal, setShowNicknameModal] = useState(false);
   const [currentSpeed, setCurrentSpeed] = useState(150);
+  const [result, setResult] = useState(null); // 서버가 검증한 결과
 
   const gameLoopRef = useRef(null);
-  const directionRef = useRef(direction);
   const directionQueueRef = useRef([]);
-
-  // 방향 ref 동기화
-  useEffect(() => {
-    directionRef.current = direction;
-  }, [direction]);
-
-  // 랜덤 음식 생성
-  const generateFood = useCallback((currentSnake) => {
-    let newFood;
-    do {
-      newFood = {
-        x: Math.floor(Math.random() * GRID_SIZE),
-        y: Math.floor(Math.random() * GRID_SIZE)
-      };
-    } while (currentSnake.some(segment => segment.x === newFood.x && segment.y === newFood.y));
-    return newFood;
-  }, []);
+  // 틱 단위 게임 상태 (서버 시뮬레이션과 같은 순서로 진행)
+  const gameRef = useRef(null);
+
+  // 서버 세션 훅
+  const {
+    sessionId,
+    startSession,
+    finishSession,
+    submitScore,
+    resetSession,
+  } = useServerGameSession('snake');
 
   // 게임 시작
-  const startGame = useCallback((selectedLevel) => {
+  const startGame = useCallback(async (selectedLevel) => {
+    let data;
+    try {
+      data = await startSession({ level: selectedLevel });
+    } catch (err) {
+      console.error('Failed to start game:', err);
+      alert('게임 시작에 실패했습니다. 다시 시도해주세요.');
+      return;
+    }
+
     const config = LEVEL_CONFIG[selectedLevel];
-    setLevel(selectedLevel);
-    setSnake([{ x: 10, y: 10 }]);
-    setDirection('RIGHT');
-    directionRef.current = 'RIGHT';
+    gameRef.current = {
+      snake: [{ x: 10, y: 10 }],
+      food: { x: 15, y: 10 }, // 첫 음식은 고정 위치
+      direction: 'RIGHT',
+      speed: config.speed,
+      score: 0,
+      tick: 0,
+      rng: createMulberry32(data.seed),
+      moves: [],
+    };
     directionQueueRef.current = [];
-    setFood({ x: 15, y: 10 });
+    setLevel(selectedLevel);
+    setSnake(gameRef.current.snake);
+    setFood(gameRef.current.food);
     setScore(0);
     setCurrentSpeed(config.speed);
+    setResult(null);
     setGameState('playing');
-  }, []);
+  }, [startSession]);
+
+  // 게임 종료: 이동 기록을 서버에서 재생해 점수 확정
+  const finishGame = useCallback(async () => {
+    setGameState('gameover');
+    try {
+      const verified = await finishSession({ moves: gameRef.current.moves });
+      setResult(verified);
+      if (!verified.canSubmit && verified.message) {
+        console.warn('Snake run rejected:', verified.message);
+      }
+    } catch (err) {
+      console.error('Failed to finish game:', err);
+      setResult({ canSubmit: false });
+    }
+  }, [finishSession]);
 
   // 게임 리셋
   const resetGame = () => {
+    resetSession();
+    setResult(null);
     setGameState('levelSelect');
   };
 
@@ -87,70 +136,75 @@ function Snake() {
     const config = LEVEL_CONFIG[level];
 
     const moveSnake = () => {
-      // 방향 큐에서 다음 방향 가져오기
+      const game = gameRef.current;
+
+      // 방향 큐에서 다음 방향 가져오기 (적용된 전환은 틱 번호와 함께 기록)
       if (directionQueueRef.current.length > 0) {
         const nextDir = directionQueueRef.current.shift();
-        if (nextDir !== OPPOSITE[directionRef.current]) {
-          directionRef.current = nextDir;
-          setDirection(nextDir);
+        if (nextDir !== OPPOSITE[game.direction]) {
+          game.direction = nextDir;
+          game.moves.push({ t: game.tick, d: DIRECTION_CODES[nextDir] });
         }
       }
+      game.tick++;
 
-      setSnake(prevSnake => {
-        const head = prevSnake[0];
-        const dir = DIRECTIONS[directionRef.current];
-        const newHead = {
-          x: head.x + dir.x,
-          y: head.y + dir.y
-        };
-
-        // 벽 충돌 체크
-        if (newHead.x < 0 || newHead.x >= GRID_SIZE || 
-            newHead.y < 0 || newHead.y >= GRID_SIZE) {
-          setGameState('gameover');
-          return prevSnake;
-        }
+      const head = game.snake[0];
+      const dir = DIRECTIONS[game.direction];
+      const newHead = {
+        x: head.x + dir.x,
+        y: head.y + dir.y
+      };
 
-        // 자기 몸 충돌 체크
-        if (prevSnake.some(segment => segment.x === newHead.x && segment.y === newHead.y)) {
-          setGameState('gameover');
-          return prevSnake;
-        }
+      // 벽 충돌 체크
+      if (newHead.x < 0 || newHead.x >= GRID_SIZE || 
+          newHead.y < 0 || newHead.y >= GRID_SIZE) {
+        clearInterval(gameLoopRef.current);
+        finishGame();
+        return;
+      }
+
+      // 자기 몸 충돌 체크
+      if (game.snake.some(segment => segment.x === newHead.x && segment.y === newHead.y)) {
+        clearInterval(gameLoopRef.current);
+        finishGame();
+        return;
+      }
 
-        const newSnake = [newHead, ...prevSnake];
-
-        // 음식 먹기 체크
-        setFood(prevFood => {
-          if (newHead.x === prevFood.x && newHead.y === prevFood.y) {
-            setScore(s => {
-              const newScore = s + 10;
-              // 속도 증가 (레벨에 따라)
-              if (config.speedIncrease > 0) {
-                setCurrentSpeed(speed => Math.max(config.minSpeed, speed - config.speedIncrease));
-              }
-              return newScore;
-            });
-            return generateFood(newSnake);
-          }
-          return prevFood;
-        });
+      const newSnake = [newHead, ...game.snake];
 
+      // 음식 먹기 체크
+      if (newHead.x === game.food.x && newHead.y === game.food.y) {
+        game.score += 10;
+        // 속도 증가 (레벨에 따라)
+        if (config.speedIncrease > 0) {
+          game.speed = Math.max(config.minSpeed, game.speed - config.speedIncrease);
+        }
+        // 판이 가득 차면 게임 종료
+        if (newSnake.length === GRID_SIZE * GRID_SIZE) {
+          game.snake = newSnake;
+          setSnake(newSnake);
+          setScore(game.score);
+          clearInterval(gameLoopRef.current);
+          finishGame();
+          return;
+        }
+        game.food = generateFood(game.rng, newSnake);
+      } else {
         // 음식을 먹지 않았으면 꼬리 제거
-        setFood(prevFood => {
-          if (newHead.x !== prevFood.x || newHead.y !== prevFood.y) {
-            newSnake.pop();
-          }
-          return prevFood;
-        });
-
-        return newSnake;
-      });
+        newSnake.pop();
+      }
+
+      game.snake = newSnake;
+      setSnake(newSnake);
+      setFood(game.food);
+      setScore(game.score);
+      setCurrentSpeed(game.speed);
     };
 
     gameLoopRef.current = setInterval(moveSnake, currentSpeed);
 
     return () => clearInterval(gameLoopRef.current);
-  }, [gameState, level, currentSpeed, generateFood]);
+  }, [gameState, level, currentSpeed, finishGame]);
 
   // 키보드 입력 처리
   useEffect(() => {
@@ -189,7 +243,7 @@ function Snake() {
       // 마지막 방향(큐의 마지막 또는 현재 방향)과 반대 방향이 아닌지 확인
       const lastDirection = directionQueueRef.current.length > 0 
         ? directionQueueRef.current[directionQueueRef.current.length - 1]
-        : directionRef.current;
+        : gameRef.current.direction;
 
       if (newDirection !== OPPOSITE[lastDirection] && newDirection !== lastDirection) {
         directionQueueRef.current.push(newDirection);
@@ -210,7 +264,7 @@ function Snake() {
 
     const lastDirection = directionQueueRef.current.length > 0 
       ? directionQueueRef.current[directionQueueRef.current.length - 1]
-      : directionRef.current;
+      : gameRef.current.direction;
 
     if (newDirection !== OPPOSITE[lastDirection] && newDirection !== lastDirection) {
       directionQueueRef.current.push(newDirection);
@@ -220,12 +274,21 @@ function Snake() {
     }
   };
 
-  // 게임 오버 시 최고 점수 체크
+  // 서버 검증이 끝나면 최고 점수 체크
   useEffect(() => {
-    if (gameState === 'gameover' && checkAndUpdateHighScore(score)) {
+    if (gameState === 'gameover' && result?.canSubmit && checkAndUpdateHighScore(result.finalScore)) {
       setShowNicknameModal(true);
     }
-  }, [gameState, score, checkAndUpdateHighScore]);
+  }, [gameState, result, checkAndUpdateHighScore]);
+
+  // 닉네임 제출 핸들러
+  const handleNicknameSubmit = async (nickname) => {
+    const submitted = await submitScore(nickname);
+    if (!submitted.success) {
+      throw new Error('Score submission failed');
+    }
+    setShowNicknameModal(false);
+  };
 
   return (
     <div className="snake-container">
@@ -334,6 +397,9 @@ function Snake() {
             <h2 className="pixel-font">GAME OVER</h2>
             <p className="final-score">최종 점수: {score}</p>
             <p className="snake-length">뱀 길이: {snake.length}</p>
+            {result && !result.canSubmit && result.message && (
+              <p className="snake-length">기록이 인정되지 않았습니다: {result.message}</p>
+            )}
             <button className="restart-btn" onClick={resetGame}>
               다시 하기
             </button>
@@ -387,9 +453,10 @@ function Snake() {
       {/* 닉네임 모달 */}
       {showNicknameModal && (
         <NicknameModal
-          score={score}
+          score={result.finalScore}
           gameName="snake"
-          onSubmit={() => setShowNicknameModal(false)}
+          sessionId={sessionId}
+          onSubmit={handleNicknameSubmit}
           onClose={() => setShowNicknameModal(false)}
         />
       )}
@@ -27,6 +27,7 @@ function SpeedClickBattle({ nickname, opponentNickname, playerIndex, sendMessage
           size: data.size,
           timeLimit: data.timeLimit,
           timeLeft: data.timeLimit,
+          spawnedAt: performance.now(), // 반응 시간 측정용
         });
         setLastResult(null);
       }),
@@ -83,7 +84,12 @@ function SpeedClickBattle({ nickname, opponentNickname, playerIndex, sendMessage
   // Handle ball click
   const handleBallClick = useCallback((e) => {
     e.stopPropagation();
-    if (!ball) return;
+    if (!ball || !gameAreaRef.current) return;
+
+    // 클릭 위치를 게임 좌표로 변환 (서버가 공 위에 있는지 검증)
+    const rect = gameAreaRef.current.getBoundingClientRect();
+    const x = (e.clientX - rect.left) * (GAME_WIDTH / rect.width);
+    const y = (e.clientY - rect.top) * (GAME_HEIGHT / rect.height);
 
     // Show click effect
     setClickEffect({
@@ -94,7 +100,13 @@ function SpeedClickBattle({ nickname, opponentNickname, playerIndex, sendMessage
     setTimeout(() => setClickEffect(null), 300);
 
     // Send click to server
-    sendMessage({ type: 'click' });
+    sendMessage({
+      type: 'click',
+      ballId: ball.id,
+      x,
+      y,
+      elapsed: (performance.now() - ball.spawnedAt) / 1000, // 초
+    });
   }, [ball, sendMessage]);
 
   // Timer bar ratio
@@ -68,6 +68,9 @@ function generateBall(seed, index, score, prevBallEndTime) {
   };
 }
 
+// 서버가 발급한 서명된 세션 토큰 헤더
+const SESSION_TOKEN_HEADER = 'X-Session-Token';
+
 /**
  * Custom hook for managing SpeedClick game sessions
  */
@@ -79,6 +82,33 @@ export function useSpeedClickSession() {
   const [error, setError] = useState(null);
   
   const clientStartTimeRef = useRef(null);
+  // 세션 토큰은 응답마다 갱신되므로 ref로 보관
+  const tokenRef = useRef(null);
+  // 서버가 받아들인 클릭/놓침 기록 (제출 시 해시 체인 검증용)
+  const clicksRef = useRef([]);
+  const missesRef = useRef([]);
+
+  /**
+   * POST to a session endpoint, sending and refreshing the session token
+   */
+  const postSession = useCallback(async (action, body) => {
+    const headers = { 'Content-Type': 'application/json' };
+    if (tokenRef.current) {
+      headers[SESSION_TOKEN_HEADER] = tokenRef.current;
+    }
+
+    const response = await fetch(`/api/game/speedclick/${action}`, {
+      method: 'POST',
+      headers,
+      body: body ? JSON.stringify(body) : undefined,
+    });
+
+    const token = response.headers.get(SESSION_TOKEN_HEADER);
+    if (token) {
+      tokenRef.current = token;
+    }
+    return response;
+  }, []);
 
   /**
    * Start a new game session
@@ -87,11 +117,12 @@ export function useSpeedClickSession() {
     setIsLoading(true);
     setError(null);
     
+    tokenRef.current = null;
+    clicksRef.current = [];
+    missesRef.current = [];
+
     try {
-      const response = await fetch('/api/game/speedclick/start', {
-        method: 'POST',
-        headers: { 'Content-Type': 'application/json' },
-      });
+      const response = await postSession('start');
       
       if (!response.ok) {
         throw new Error('Failed to start session');
@@ -114,7 +145,7 @@ export function useSpeedClickSession() {
     } finally {
       setIsLoading(false);
     }
-  }, []);
+  }, [postSession]);
 
   /**
    * Report a ball click to the server
@@ -127,26 +158,31 @@ export function useSpeedClickSession() {
     const clickTimeMs = Date.now() - clientStartTimeRef.current;
     
     try {
-      const response = await fetch('/api/game/speedclick/click', {
-        method: 'POST',
-        headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({
-          sessionId,
-          ballIndex,
-          clickTimeMs,
-        }),
+      const response = await postSession('click', {
+        sessionId,
+        ballIndex,
+        clickTimeMs,
       });
       
       if (!response.ok) {
         throw new Error('Failed to report click');
       }
       
-      return await response.json();
+      const result = await response.json();
+      if (result.valid) {
+        clicksRef.current.push({
+          ball_index: ballIndex,
+          click_time: clickTimeMs,
+          valid: true,
+          points: result.points,
+        });
+      }
+      return result;
     } catch (err) {
       setError(err.message);
       throw err;
     }
-  }, [sessionId]);
+  }, [sessionId, postSession]);
 
   /**
    * Report a missed ball to the server
@@ -157,25 +193,25 @@ export function useSpeedClickSession() {
     }
     
     try {
-      const response = await fetch('/api/game/speedclick/miss', {
-        method: 'POST',
-        headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({
-          sessionId,
-          ballIndex,
-        }),
+      const response = await postSession('miss', {
+        sessionId,
+        ballIndex,
       });
       
       if (!response.ok) {
         throw new Error('Failed to report miss');
       }
       
-      return await response.json();
+      const result = await response.json();
+      if (result.valid) {
+        missesRef.current.push(ballIndex);
+      }
+      return result;
     } catch (err) {
       setError(err.message);
       throw err;
     }
-  }, [sessionId]);
+  }, [sessionId, postSession]);
 
   /**
    * End the current game session
@@ -186,11 +222,7 @@ export function useSpeedClickSession() {
     }
     
     try {
-      const response = await fetch('/api/game/speedclick/end', {
-        method: 'POST',
-        headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({ sessionId }),
-      });
+      const response = await postSession('end', { sessionId });
       
       if (!response.ok) {
         throw new Error('Failed to end session');
@@ -201,7 +233,7 @@ export function useSpeedClickSession() {
       setError(err.message);
       throw err;
     }
-  }, [sessionId]);
+  }, [sessionId, postSession]);
 
   /**
    * Submit the score with a nickname
@@ -212,13 +244,11 @@ export function useSpeedClickSession() {
     }
     
     try {
-      const response = await fetch('/api/game/speedclick/submit', {
-        method: 'POST',
-        headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({
-          sessionId,
-          nickname,
-        }),
+      const response = await postSession('submit', {
+        sessionId,
+        nickname,
+        clicks: clicksRef.current,
+        misses: missesRef.current,
       });
       
       if (!response.ok) {
@@ -232,6 +262,7 @@ export function useSpeedClickSession() {
         setSessionId(null);
         setSeed(null);
         setServerStartTime(null);
+        tokenRef.current = null;
       }
       
       return result;
@@ -239,7 +270,7 @@ export function useSpeedClickSession() {
       setError(err.message);
       throw err;
     }
-  }, [sessionId]);
+  }, [sessionId, postSession]);
 
   /**
    * Reset the session state
@@ -250,6 +281,9 @@ export function useSpeedClickSession() {
     setServerStartTime(null);
     setError(null);
     clientStartTimeRef.current = null;
+    tokenRef.current = null;
+    clicksRef.current = [];
+    missesRef.current = [];
   }, []);
 
   /**
@@ -277,4 +311,90 @@ export function useSpeedClickSession() {
   };
 }
 
+/**
+ * Custom hook for server-verified game sessions (/api/game/{game}/{action})
+ * whose state the server keeps (Snake, JumpRunner, MemoryCard)
+ */
+export function useServerGameSession(game) {
+  const [sessionId, setSessionId] = useState(null);
+  // 연속된 요청(예: 카드 뒤집기)이 항상 현재 세션을 쓰도록 ref로도 보관
+  const sessionIdRef = useRef(null);
+
+  /**
+   * POST to a session endpoint; every action but start carries the session ID
+   */
+  const postSession = useCallback(async (action, body) => {
+    const payload = action === 'start'
+      ? body
+      : { ...body, sessionId: sessionIdRef.current };
+
+    const response = await fetch(`/api/game/${game}/${action}`, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: payload ? JSON.stringify(payload) : undefined,
+    });
+
+    if (!response.ok) {
+      throw new Error(`Failed to ${action} session`);
+    }
+    return response.json();
+  }, [game]);
+
+  /**
+   * Start a new game session
+   */
+  const startSession = useCallback(async (params) => {
+    sessionIdRef.current = null;
+    setSessionId(null);
+
+    const data = await postSession('start', params);
+    sessionIdRef.current = data.sessionId;
+    setSessionId(data.sessionId);
+    return data;
+  }, [postSession]);
+
+  /**
+   * Send an in-game event such as "flip"
+   */
+  const sendEvent = useCallback((event, body) => {
+    return postSession(event, body);
+  }, [postSession]);
+
+  /**
+   * Finish the session, sending any log the server replays
+   */
+  const finishSession = useCallback((body) => {
+    return postSession('finish', body);
+  }, [postSession]);
+
+  /**
+   * Submit the verified score with a nickname
+   */
+  const submitScore = useCallback(async (nickname) => {
+    const result = await postSession('submit', { nickname });
+    if (result.success) {
+      sessionIdRef.current = null;
+      setSessionId(null);
+    }
+    return result;
+  }, [postSession]);
+
+  /**
+   * Reset the session state
+   */
+  const resetSession = useCallback(() => {
+    sessionIdRef.current = null;
+    setSessionId(null);
+  }, []);
+
+  return {
+    sessionId,
+    startSession,
+    sendEvent,
+    finishSession,
+    submitScore,
+    resetSession,
+  };
+}
+
 export { generateBall, createMulberry32, getLevelConfig, LEVELS };
@@ -1,5 +1,8 @@
 import { useState, useEffect, useRef, useCallback } from 'react';
 
+// 서버 프로토콜 버전 (연결 직후 hello로 알림)
+const PROTOCOL_VERSION = 1;
+
 const getWebSocketURL = () => {
   const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
   const host = window.location.host;
@@ -21,6 +24,7 @@ export default function useWebSocket() {
     const ws = new WebSocket(getWebSocketURL());
 
     ws.onopen = () => {
+      ws.send(JSON.stringify({ type: 'hello', version: PROTOCOL_VERSION }));
       setIsConnected(true);
       console.log('WebSocket connected');
     };
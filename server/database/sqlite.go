@@ -36,6 +36,85 @@ func Init() error {
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
 	CREATE INDEX IF NOT EXISTS idx_game_score ON scores(game, score DESC);
+
+	CREATE TABLE IF NOT EXISTS speedclick_replays (
+		score_id INTEGER PRIMARY KEY REFERENCES scores(id),
+		seed INTEGER NOT NULL,
+		clicks TEXT NOT NULL,
+		misses TEXT NOT NULL,
+		flagged INTEGER NOT NULL DEFAULT 0,
+		verified_at DATETIME
+	);
+
+	CREATE TABLE IF NOT EXISTS game_sessions (
+		id TEXT PRIMARY KEY,
+		game TEXT NOT NULL,
+		seed INTEGER NOT NULL,
+		level TEXT NOT NULL DEFAULT '',
+		start_time INTEGER NOT NULL,
+		score INTEGER NOT NULL DEFAULT 0,
+		status TEXT NOT NULL,
+		state TEXT,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_game_sessions_start ON game_sessions(start_time);
+
+	CREATE TABLE IF NOT EXISTS session_events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL,
+		event TEXT NOT NULL,
+		payload TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_session_events_session ON session_events(session_id);
+
+	CREATE TABLE IF NOT EXISTS submitted_sessions (
+		session_id TEXT PRIMARY KEY,
+		game TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS battle_matches (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		room_code TEXT NOT NULL,
+		nickname1 TEXT NOT NULL,
+		nickname2 TEXT NOT NULL,
+		score1 INTEGER NOT NULL,
+		score2 INTEGER NOT NULL,
+		winner INTEGER NOT NULL,
+		rounds INTEGER NOT NULL DEFAULT 1,
+		duration REAL NOT NULL,
+		ball_count INTEGER NOT NULL,
+		rating1_before REAL NOT NULL,
+		rating2_before REAL NOT NULL,
+		rating1_after REAL NOT NULL,
+		rating2_after REAL NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_battle_matches_nickname1 ON battle_matches(nickname1);
+	CREATE INDEX IF NOT EXISTS idx_battle_matches_nickname2 ON battle_matches(nickname2);
+
+	CREATE TABLE IF NOT EXISTS battle_replays (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		room_code TEXT NOT NULL,
+		match_id INTEGER REFERENCES battle_matches(id),
+		events TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_battle_replays_match_id ON battle_replays(match_id);
+	CREATE INDEX IF NOT EXISTS idx_battle_replays_room_code ON battle_replays(room_code);
+
+	CREATE TABLE IF NOT EXISTS battle_ratings (
+		nickname TEXT PRIMARY KEY,
+		rating REAL NOT NULL,
+		games INTEGER NOT NULL DEFAULT 0,
+		wins INTEGER NOT NULL DEFAULT 0,
+		losses INTEGER NOT NULL DEFAULT 0,
+		draws INTEGER NOT NULL DEFAULT 0,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_battle_ratings_rating ON battle_ratings(rating DESC);
 	`
 
 	_, err = DB.Exec(createTable)
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+
+	"mini-games/model"
+	"mini-games/service"
+)
+
+// 관리자 API 토큰 (미설정 시 관리자 API 비활성화)
+var adminToken = os.Getenv("ADMIN_TOKEN")
+
+// checkAdmin validates the X-Admin-Token header and writes an error response if invalid
+func checkAdmin(w http.ResponseWriter, r *http.Request) bool {
+	token := r.Header.Get("X-Admin-Token")
+	if adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// HandleSpeedClickVerify handles /api/admin/speedclick/verify
+//
+// GET re-verifies stored SpeedClick scores from their replay logs and only
+// reports the results; POST also records them and flags mismatches.
+// Optional query params: scoreId, limit.
+func HandleSpeedClickVerify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !checkAdmin(w, r) {
+		return
+	}
+
+	var scoreID int64
+	if s := r.URL.Query().Get("scoreId"); s != "" {
+		parsed, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "Invalid scoreId", http.StatusBadRequest)
+			return
+		}
+		scoreID = parsed
+	}
+
+	limit := 100
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 1000 {
+			limit = parsed
+		}
+	}
+
+	results, err := service.VerifyStoredSpeedClickScores(scoreID, limit)
+	if err != nil {
+		http.Error(w, "Failed to verify scores", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		if err := service.RecordSpeedClickVerifications(results); err != nil {
+			http.Error(w, "Failed to record verification", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if results == nil {
+		results = []model.ReplayVerification{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"mini-games/model"
+	"mini-games/service"
+)
+
+// HandleBattleLeaderboard handles GET /api/battle/leaderboard
+func HandleBattleLeaderboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := 10
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	ratings, err := service.GetBattleLeaderboard(limit)
+	if err != nil {
+		http.Error(w, "Failed to get leaderboard", http.StatusInternalServerError)
+		return
+	}
+
+	if ratings == nil {
+		ratings = []model.BattleRating{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ratings)
+}
+
+// HandleBattleHistory handles GET /api/battle/history?nickname=
+func HandleBattleHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	nickname := strings.TrimSpace(r.URL.Query().Get("nickname"))
+	if nickname == "" || len(nickname) > 20 || !validNicknameRegex.MatchString(nickname) {
+		http.Error(w, "Invalid nickname", http.StatusBadRequest)
+		return
+	}
+
+	limit := 20
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	matches, err := service.GetBattleHistory(nickname, limit)
+	if err != nil {
+		http.Error(w, "Failed to get history", http.StatusInternalServerError)
+		return
+	}
+
+	if matches == nil {
+		matches = []model.BattleMatch{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(matches)
+}
+
+// HandleBattleRooms handles GET /api/battle/rooms?state=
+func HandleBattleRooms(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 상태 필터 (예: waiting이면 참가 가능한 방만)
+	state := model.RoomState(strings.TrimSpace(r.URL.Query().Get("state")))
+
+	rooms := []model.LobbyRoom{}
+	for _, room := range roomManager.PublicRooms() {
+		if state == "" || room.State == state {
+			rooms = append(rooms, room)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rooms)
+}
+
+// HandleBattleReplay handles GET /api/battle/replay/{id}, a rated match's
+// replay with /api/battle/replay?match={matchId}, and a room's replay IDs
+// with ?room={code}
+func HandleBattleReplay(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if roomCode := r.URL.Query().Get("room"); roomCode != "" {
+		ids, err := service.GetBattleReplaysByRoom(roomCode, 20)
+		if err != nil {
+			http.Error(w, "Failed to get replays", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ids)
+		return
+	}
+
+	var data []byte
+	var err error
+	if match := r.URL.Query().Get("match"); match != "" {
+		matchID, parseErr := strconv.ParseInt(match, 10, 64)
+		if parseErr != nil || matchID <= 0 {
+			http.Error(w, "Invalid match id", http.StatusBadRequest)
+			return
+		}
+		data, err = service.GetBattleReplayByMatch(matchID)
+	} else {
+		id, parseErr := strconv.ParseInt(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/battle/replay/"), "/"), 10, 64)
+		if parseErr != nil || id <= 0 {
+			http.Error(w, "Invalid replay id", http.StatusBadRequest)
+			return
+		}
+		data, err = service.GetBattleReplay(id)
+	}
+
+	if errors.Is(err, service.ErrReplayNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get replay", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
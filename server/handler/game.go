@@ -2,138 +2,137 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"regexp"
 	"strings"
 
-	"mini-games/model"
 	"mini-games/service"
 )
 
-var validNicknameRegexGame = regexp.MustCompile(`^[a-zA-Z0-9가-힣_\-\s]+$`)
+const MAX_MOVE_LOG_SIZE = 256 * 1024 // 256KB max move log body
 
-// HandleSpeedClickStart handles POST /api/game/speedclick/start
-func HandleSpeedClickStart(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	session := service.CreateSpeedClickSession()
+// 상태 없는 게임의 서명된 세션 토큰 헤더 (요청/응답 공통)
+const SESSION_TOKEN_HEADER = "X-Session-Token"
 
-	response := model.StartGameResponse{
-		SessionID: session.ID,
-		Seed:      session.Seed,
-		StartTime: session.StartTime.UnixMilli(),
-	}
+var validNicknameRegexGame = regexp.MustCompile(`^[a-zA-Z0-9가-힣_\-\s]+$`)
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+// 기존 URL 호환용 게임 ID 별칭
+var gameAliases = map[string]string{
+	"speedclick": "speed-click",
 }
 
-// HandleSpeedClickClick handles POST /api/game/speedclick/click
-func HandleSpeedClickClick(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+// checkNickname validates a nickname and writes an error response if invalid
+func checkNickname(w http.ResponseWriter, nickname string) bool {
+	if nickname == "" || len(nickname) > 20 {
+		http.Error(w, "Invalid nickname", http.StatusBadRequest)
+		return false
 	}
-
-	var req model.ClickRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
+	if !validNicknameRegexGame.MatchString(nickname) {
+		http.Error(w, "Nickname contains invalid characters", http.StatusBadRequest)
+		return false
 	}
+	return true
+}
 
-	if req.SessionID == "" {
-		http.Error(w, "Session ID required", http.StatusBadRequest)
-		return
+// writeGameError maps engine errors to HTTP responses
+func writeGameError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrUnknownGame), errors.Is(err, service.ErrUnknownEvent),
+		errors.Is(err, service.ErrSessionNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidToken), errors.Is(err, service.ErrTokenExpired):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, service.ErrAlreadySubmitted):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrScoreNotSaved):
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
 	}
-
-	response := service.ProcessClick(req.SessionID, req.BallIndex, req.ClickTimeMs)
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
 }
 
-// HandleSpeedClickMiss handles POST /api/game/speedclick/miss
-func HandleSpeedClickMiss(w http.ResponseWriter, r *http.Request) {
+// HandleGame handles POST /api/game/{game}/{action}
+//
+// start and submit are common to every game, end/finish close the session,
+// and any other action is forwarded to the game as an in-game event.
+func HandleGame(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	var req model.MissRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/game/"), "/"), "/")
+	if len(parts) != 2 {
+		http.NotFound(w, r)
 		return
 	}
-
-	if req.SessionID == "" {
-		http.Error(w, "Session ID required", http.StatusBadRequest)
+	gameID, action := parts[0], parts[1]
+	if alias, ok := gameAliases[gameID]; ok {
+		gameID = alias
+	}
+	if !allowedGames[gameID] {
+		http.Error(w, "Invalid game", http.StatusNotFound)
 		return
 	}
 
-	response := service.ProcessMiss(req.SessionID, req.BallIndex)
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
-}
-
-// HandleSpeedClickEnd handles POST /api/game/speedclick/end
-func HandleSpeedClickEnd(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	// Limit request body size (move logs can be large)
+	r.Body = http.MaxBytesReader(w, r.Body, MAX_MOVE_LOG_SIZE)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
 		return
 	}
 
-	var req model.EndGameRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
+	var req struct {
+		SessionID string `json:"sessionId"`
+		Nickname  string `json:"nickname"`
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
 	}
 
-	if req.SessionID == "" {
+	if action != "start" && req.SessionID == "" {
 		http.Error(w, "Session ID required", http.StatusBadRequest)
 		return
 	}
 
-	response := service.EndSpeedClickSession(req.SessionID)
+	// Signed session token for stateless games
+	token := r.Header.Get(SESSION_TOKEN_HEADER)
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
-}
+	var response interface{}
+	var newToken string
+	switch action {
+	case "start":
+		response, newToken, err = service.StartSession(gameID, body)
 
-// HandleSpeedClickSubmit handles POST /api/game/speedclick/submit
-func HandleSpeedClickSubmit(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
+	case "end", "finish":
+		response, newToken, err = service.FinishSession(gameID, req.SessionID, token, body)
 
-	var req model.SubmitScoreRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
+	case "submit":
+		// Validate nickname
+		req.Nickname = strings.TrimSpace(req.Nickname)
+		if !checkNickname(w, req.Nickname) {
+			return
+		}
+		response, err = service.SubmitSessionScore(gameID, req.SessionID, token, req.Nickname, body)
 
-	if req.SessionID == "" {
-		http.Error(w, "Session ID required", http.StatusBadRequest)
-		return
+	default:
+		response, newToken, err = service.ApplyEvent(gameID, req.SessionID, token, action, body)
 	}
 
-	// Validate nickname
-	req.Nickname = strings.TrimSpace(req.Nickname)
-	if req.Nickname == "" || len(req.Nickname) > 20 {
-		http.Error(w, "Invalid nickname", http.StatusBadRequest)
-		return
-	}
-	if !validNicknameRegexGame.MatchString(req.Nickname) {
-		http.Error(w, "Nickname contains invalid characters", http.StatusBadRequest)
+	if err != nil {
+		writeGameError(w, err)
 		return
 	}
 
-	response := service.SubmitSpeedClickScore(req.SessionID, req.Nickname)
-
+	if newToken != "" {
+		w.Header().Set(SESSION_TOKEN_HEADER, newToken)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
@@ -22,6 +22,13 @@ var allowedGames = map[string]bool{
 	"memory-card":  true,
 }
 
+// 서버 검증 세션(/api/game/{game}/submit)으로만 점수를 등록할 수 있는 게임
+var sessionOnlyGames = map[string]bool{
+	"snake":       true,
+	"jump-runner": true,
+	"memory-card": true,
+}
+
 var validNicknameRegex = regexp.MustCompile(`^[a-zA-Z0-9가-힣_\-\s]+$`)
 
 func HandleScores(w http.ResponseWriter, r *http.Request) {
@@ -65,6 +72,11 @@ func createScore(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if sessionOnlyGames[input.Game] {
+		http.Error(w, "Score must be submitted through a game session", http.StatusForbidden)
+		return
+	}
+
 	// Validate score (prevent manipulation)
 	if input.Score < 0 || input.Score > MAX_SCORE {
 		http.Error(w, "Invalid score", http.StatusBadRequest)
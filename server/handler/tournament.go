@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"mini-games/model"
+	"mini-games/service"
+)
+
+const MAX_TOURNAMENT_BODY_SIZE = 8 * 1024 // 참가자 64명 + 규칙
+
+// HandleTournaments handles POST /api/tournaments (admin) and
+// GET /api/tournaments/{id}
+func HandleTournaments(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/tournaments"), "/")
+	if id == "" {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		createTournament(w, r)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	tournamentID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || tournamentID <= 0 {
+		http.Error(w, "Invalid tournament id", http.StatusBadRequest)
+		return
+	}
+
+	tournament, err := roomManager.GetTournament(tournamentID)
+	if errors.Is(err, service.ErrTournamentNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get tournament", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tournament)
+}
+
+func createTournament(w http.ResponseWriter, r *http.Request) {
+	if !checkAdmin(w, r) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MAX_TOURNAMENT_BODY_SIZE)
+
+	var input struct {
+		Name     string          `json:"name"`
+		Format   string          `json:"format"`
+		Entrants []string        `json:"entrants"` // 시드 순
+		Rules    json.RawMessage `json:"rules"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		if err.Error() == "http: request body too large" {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" || len(input.Name) > 50 {
+		http.Error(w, "Invalid name", http.StatusBadRequest)
+		return
+	}
+	if input.Format == "" {
+		input.Format = model.TournamentSingle
+	}
+
+	for i, nickname := range input.Entrants {
+		nickname = strings.TrimSpace(nickname)
+		if nickname == "" || len(nickname) > 20 || !validNicknameRegex.MatchString(nickname) {
+			http.Error(w, "Invalid entrant nickname", http.StatusBadRequest)
+			return
+		}
+		input.Entrants[i] = nickname
+	}
+
+	rules, err := service.ParseRoomRules(input.Rules)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tournament, err := roomManager.CreateTournament(input.Name, input.Format, input.Entrants, rules)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tournament)
+}
@@ -1,20 +1,25 @@
 package handler
 
 import (
-	"encoding/json"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
 
 	"mini-games/model"
+	"mini-games/protocol"
 	"mini-games/service"
 )
 
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
+	// 클라이언트가 제안한 것 중 바이너리 우선, 제안이 없으면 JSON
+	Subprotocols: []string{protocol.SubprotocolBinary, protocol.SubprotocolJSON},
 	CheckOrigin: func(r *http.Request) bool {
 		return true // Allow all origins for development
 	},
@@ -25,15 +30,36 @@ var roomManager *service.RoomManager
 // InitWebSocket initializes the WebSocket handler
 func InitWebSocket() {
 	roomManager = service.NewRoomManager()
+
+	// 재접속 대기 시간 (초, 0이면 즉시 퇴장)
+	if value := os.Getenv("RECONNECT_GRACE_SECONDS"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			roomManager.ReconnectGrace = time.Duration(seconds) * time.Second
+		}
+	}
+
+	// 방당 최대 관전자 수
+	if value := os.Getenv("MAX_SPECTATORS"); value != "" {
+		if count, err := strconv.Atoi(value); err == nil && count >= 0 {
+			roomManager.MaxSpectators = count
+		}
+	}
+
+	// 채팅 금칙어 (쉼표로 구분)
+	if value := os.Getenv("CHAT_BANNED_WORDS"); value != "" {
+		roomManager.ChatFilter = service.NewWordFilter(strings.Split(value, ","))
+	}
 }
 
 // HandleBattleWS handles WebSocket connections for battle mode
 func HandleBattleWS(w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
+	ws, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade error: %v", err)
 		return
 	}
+	// 쓰기는 모두 conn의 전송 대기열을 거침 (읽기만 ws 직접 사용)
+	conn := model.NewConn(ws)
 
 	player := &model.Player{
 		Conn:       conn,
@@ -42,29 +68,49 @@ func HandleBattleWS(w http.ResponseWriter, r *http.Request) {
 
 	var roomCode string
 	var room *model.Room
+	var spectating *model.Room // 관전 중인 방 (플레이어와 동시에 불가)
+	var ticket *service.QueueTicket
 
 	defer func() {
 		conn.Close()
+		roomManager.UnsubscribeLobby(player)
+		if ticket != nil && !roomManager.CancelQueue(ticket) {
+			if matched := roomManager.TicketRoom(ticket); matched != nil {
+				roomCode = matched.Code
+			}
+		}
+		if spectating != nil {
+			roomManager.RemoveSpectator(spectating, player)
+		}
 		if roomCode != "" {
-			roomManager.RemovePlayerFromRoom(roomCode, player.Index)
+			// 재접속 대기 시간 동안 자리 유지
+			roomManager.DisconnectPlayer(roomCode, player, conn)
 		}
 	}()
 
+	// 프레임 크기 제한 (초과 시 1009로 종료)
+	ws.SetReadLimit(protocol.MaxFrameSize)
+
 	// Set read deadline for ping/pong
-	conn.SetReadDeadline(time.Now().Add(60 * time.Second))
-	conn.SetPongHandler(func(string) error {
-		conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+	ws.SetReadDeadline(time.Now().Add(60 * time.Second))
+	ws.SetPongHandler(func(data string) error {
+		ws.SetReadDeadline(time.Now().Add(60 * time.Second))
+		// 핑에 담아 보낸 시각으로 왕복 지연 측정
+		if sent, err := strconv.ParseInt(data, 10, 64); err == nil {
+			player.ObserveRTT(time.Since(time.Unix(0, sent)))
+		}
 		return nil
 	})
 
 	// Start ping routine
 	go func() {
-		ticker := time.NewTicker(30 * time.Second)
+		ticker := time.NewTicker(service.PingInterval)
 		defer ticker.Stop()
 		for {
 			select {
 			case <-ticker.C:
-				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second)); err != nil {
+				payload := []byte(strconv.FormatInt(time.Now().UnixNano(), 10))
+				if err := ws.WriteControl(websocket.PingMessage, payload, time.Now().Add(10*time.Second)); err != nil {
 					return
 				}
 			}
@@ -72,84 +118,367 @@ func HandleBattleWS(w http.ResponseWriter, r *http.Request) {
 	}()
 
 	// Message handling loop
+	handshaken := false
 	for {
-		_, message, err := conn.ReadMessage()
+		frameType, message, err := ws.ReadMessage()
 		if err != nil {
+			// 최대 크기를 넘는 프레임은 gorilla가 1009로 연결을 닫음
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("WebSocket error: %v", err)
 			}
 			break
 		}
 
-		var msg struct {
-			Type     string `json:"type"`
-			RoomCode string `json:"roomCode,omitempty"`
-			Nickname string `json:"nickname,omitempty"`
+		var decoded protocol.Message
+		if frameType != websocket.TextMessage {
+			err = protocol.ErrUnsupportedFrame
+		} else {
+			decoded, err = protocol.Decode(message)
 		}
-
-		if err := json.Unmarshal(message, &msg); err != nil {
-			player.SendJSON(model.ErrorMsg{Type: "error", Message: "잘못된 메시지 형식입니다"})
+		if err == nil && !handshaken && decoded.MessageType() != protocol.TypeHello {
+			// hello 없이 시작한 클라이언트는 버전 1로 취급
+			handshaken = true
+		}
+		if err != nil {
+			if code := protocol.CloseCode(err); code != 0 {
+				closeConn(player, conn, code, err)
+				break
+			}
+			sendError(player, err)
 			continue
 		}
 
 		player.LastActive = time.Now()
 
-		switch msg.Type {
-		case "create":
-			if msg.Nickname == "" {
-				player.SendJSON(model.ErrorMsg{Type: "error", Message: "닉네임을 입력해주세요"})
+		// 매칭이 성사되었으면 해당 방으로 진행
+		if ticket != nil {
+			if matched := roomManager.TicketRoom(ticket); matched != nil {
+				room, roomCode, ticket = matched, matched.Code, nil
+			}
+		}
+
+		switch msg := decoded.(type) {
+		case *protocol.Hello:
+			if handshaken {
+				sendError(player, protocol.ErrInvalidMessage)
 				continue
 			}
-			player.Nickname = msg.Nickname
-			room, roomCode = roomManager.CreateRoom(player)
-			player.SendJSON(model.RoomCreatedMsg{Type: "room_created", RoomCode: roomCode})
+			handshaken = true
+			player.SendJSON(protocol.NewHello(ws.Subprotocol()))
 
-		case "join":
-			if msg.Nickname == "" {
-				player.SendJSON(model.ErrorMsg{Type: "error", Message: "닉네임을 입력해주세요"})
+		case *protocol.Create:
+			if roomCode != "" {
+				sendError(player, protocol.ErrAlreadyInRoom)
 				continue
 			}
-			if msg.RoomCode == "" {
-				player.SendJSON(model.ErrorMsg{Type: "error", Message: "방 코드를 입력해주세요"})
+			if spectating != nil || ticket != nil {
+				sendError(player, protocol.ErrBusy)
 				continue
 			}
-			player.Nickname = msg.Nickname
+			nickname, ok := battleNickname(player, msg.Nickname)
+			if !ok {
+				continue
+			}
+			capacity := msg.Capacity
+			if capacity == 0 {
+				capacity = service.DefaultRoomCapacity
+			}
+			if capacity < service.MinRoomCapacity || capacity > service.MaxRoomCapacity {
+				sendError(player, service.ErrInvalidCapacity)
+				continue
+			}
+			rules, err := service.ParseRoomRules(msg.Rules)
+			if err != nil {
+				sendError(player, err)
+				continue
+			}
+			player.Nickname = nickname
+			room, roomCode = roomManager.CreateRoom(player, capacity, rules)
+			if msg.Public {
+				roomManager.PublishRoom(room)
+			}
+			player.SendJSON(model.RoomCreatedMsg{
+				Type:           protocol.TypeRoomCreated,
+				RoomCode:       roomCode,
+				Capacity:       capacity,
+				Rules:          rules,
+				ReconnectToken: player.ReconnectToken,
+			})
+
+		case *protocol.PlayBot:
+			if roomCode != "" {
+				sendError(player, protocol.ErrAlreadyInRoom)
+				continue
+			}
+			if spectating != nil || ticket != nil {
+				sendError(player, protocol.ErrBusy)
+				continue
+			}
+			nickname, ok := battleNickname(player, msg.Nickname)
+			if !ok {
+				continue
+			}
+			rules, err := service.ParseRoomRules(msg.Rules)
+			if err != nil {
+				sendError(player, err)
+				continue
+			}
+			difficulty := msg.Difficulty
+			if difficulty == "" {
+				difficulty = model.DifficultyNormal
+			}
+			player.Nickname = nickname
+			botRoom, botRoomCode := roomManager.CreateRoom(player, 2, rules)
+			bot, err := roomManager.AddBot(botRoom, difficulty)
+			if err != nil {
+				roomManager.RemoveRoom(botRoomCode)
+				sendError(player, err)
+				continue
+			}
+			room, roomCode = botRoom, botRoomCode
+			player.SendJSON(model.RoomCreatedMsg{
+				Type:           protocol.TypeRoomCreated,
+				RoomCode:       roomCode,
+				Capacity:       room.Capacity,
+				Rules:          rules,
+				ReconnectToken: player.ReconnectToken,
+			})
+			player.SendJSON(model.OpponentJoinedMsg{Type: protocol.TypeOpponentJoined, Nickname: bot.Nickname})
+
+			// Start game
+			go roomManager.StartGame(room)
+
+		case *protocol.Join:
+			if roomCode != "" {
+				sendError(player, protocol.ErrAlreadyInRoom)
+				continue
+			}
+			if spectating != nil || ticket != nil {
+				sendError(player, protocol.ErrBusy)
+				continue
+			}
+			nickname, ok := battleNickname(player, msg.Nickname)
+			if !ok {
+				continue
+			}
+			player.Nickname = nickname
 			var err error
 			room, err = roomManager.JoinRoom(msg.RoomCode, player)
 			if err != nil {
-				player.SendJSON(model.ErrorMsg{Type: "error", Message: err.Error()})
+				sendError(player, err)
 				continue
 			}
 			roomCode = msg.RoomCode
 
+			if room.Capacity > 2 {
+				// 다인원 방은 방장이 start로 시작
+				room.Mu.RLock()
+				joined := model.RoomJoinedMsg{
+					Type:           protocol.TypeRoomJoined,
+					RoomCode:       roomCode,
+					PlayerIndex:    player.Index,
+					Capacity:       room.Capacity,
+					Host:           room.Host,
+					Nicknames:      make([]string, room.Capacity),
+					Rules:          room.Rules,
+					ReconnectToken: player.ReconnectToken,
+				}
+				for i, p := range room.Players {
+					if p != nil {
+						joined.Nicknames[i] = p.Nickname
+					}
+				}
+				room.Mu.RUnlock()
+				player.SendJSON(joined)
+				continue
+			}
+
 			// Notify both players
-			room.Players[0].SendJSON(model.OpponentJoinedMsg{Type: "opponent_joined", Nickname: player.Nickname})
-			player.SendJSON(model.OpponentJoinedMsg{Type: "opponent_joined", Nickname: room.Players[0].Nickname})
+			room.Mu.RLock()
+			opponent := room.Players[1-player.Index]
+			room.Mu.RUnlock()
+			if opponent == nil {
+				continue
+			}
+			opponent.SendJSON(model.OpponentJoinedMsg{Type: protocol.TypeOpponentJoined, Nickname: player.Nickname})
+			player.SendJSON(model.OpponentJoinedMsg{
+				Type:           protocol.TypeOpponentJoined,
+				Nickname:       opponent.Nickname,
+				Rules:          &room.Rules,
+				ReconnectToken: player.ReconnectToken,
+			})
 
 			// Start game
 			go roomManager.StartGame(room)
 
-		case "click":
-			if room == nil {
+		case *protocol.Rejoin:
+			if roomCode != "" || spectating != nil {
+				sendError(player, protocol.ErrAlreadyInRoom)
 				continue
 			}
-			roomManager.HandleClick(room, player.Index)
+			if ticket != nil {
+				sendError(player, protocol.ErrBusy)
+				continue
+			}
+			rejoined, existing, err := roomManager.RejoinRoom(msg.RoomCode, msg.ReconnectToken, conn)
+			if err != nil {
+				sendError(player, err)
+				continue
+			}
+			// 이 연결은 기존 플레이어로 계속 진행 (로비 구독은 교체 전 객체에 걸려 있으므로 해제)
+			roomManager.UnsubscribeLobby(player)
+			room, player, roomCode = rejoined, existing, msg.RoomCode
 
-		case "ready_rematch":
-			if room == nil {
+		case *protocol.Spectate:
+			if roomCode != "" || spectating != nil {
+				sendError(player, protocol.ErrAlreadyInRoom)
+				continue
+			}
+			player.Nickname = msg.Nickname
+			var err error
+			spectating, err = roomManager.Spectate(msg.RoomCode, player)
+			if err != nil {
+				sendError(player, err)
 				continue
 			}
-			roomManager.HandleRematchReady(room, player.Index)
 
-		case "leave":
+		case *protocol.Queue:
+			if roomCode != "" || spectating != nil || ticket != nil {
+				sendError(player, protocol.ErrAlreadyInRoom)
+				continue
+			}
+			nickname, ok := battleNickname(player, msg.Nickname)
+			if !ok {
+				continue
+			}
+			player.Nickname = nickname
+			var err error
+			ticket, err = roomManager.Enqueue(player, msg.Rated)
+			if err != nil {
+				sendError(player, err)
+				continue
+			}
+
+		case *protocol.TournamentCheckIn:
+			if spectating != nil || ticket != nil {
+				sendError(player, protocol.ErrBusy)
+				continue
+			}
+			nickname, ok := battleNickname(player, msg.Nickname)
+			if !ok {
+				continue
+			}
 			if roomCode != "" {
+				// 지난 토너먼트 경기 방은 자동으로 나감
+				if room.TournamentID == 0 {
+					sendError(player, protocol.ErrAlreadyInRoom)
+					continue
+				}
 				roomManager.RemovePlayerFromRoom(roomCode, player.Index)
-				roomCode = ""
-				room = nil
+				room, roomCode = nil, ""
+			}
+			player.Nickname = nickname
+			checkIn, err := roomManager.CheckIn(msg.TournamentID, player, msg.CheckInToken)
+			if err != nil {
+				sendError(player, err)
+				continue
+			}
+			ticket = checkIn
+
+		case *protocol.Click:
+			if room == nil {
+				continue
+			}
+			click := service.Click{BallID: msg.BallID, X: msg.X, Y: msg.Y, Elapsed: msg.Elapsed}
+			if err := roomManager.HandleClick(room, player.Index, click); err != nil {
+				sendError(player, err)
 			}
 
-		default:
-			player.SendJSON(model.ErrorMsg{Type: "error", Message: "알 수 없는 메시지 타입입니다"})
+		case *protocol.Chat:
+			if room == nil {
+				continue
+			}
+			text := strings.TrimSpace(msg.Text)
+			if !validNicknameRegex.MatchString(text) {
+				sendError(player, protocol.ErrChatInvalid)
+				continue
+			}
+			if err := roomManager.SendChat(room, player.Index, text); err != nil {
+				sendError(player, err)
+			}
+
+		case *protocol.Emote:
+			if room == nil {
+				continue
+			}
+			if err := roomManager.SendEmote(room, player.Index, msg.Emote); err != nil {
+				sendError(player, err)
+			}
+
+		case *protocol.Empty:
+			switch msg.Type {
+			case protocol.TypeStart:
+				if room == nil {
+					continue
+				}
+				if err := roomManager.HostStart(room, player.Index); err != nil {
+					sendError(player, err)
+				}
+
+			case protocol.TypeLobbySubscribe:
+				roomManager.SubscribeLobby(player)
+
+			case protocol.TypeLobbyUnsubscribe:
+				roomManager.UnsubscribeLobby(player)
+
+			case protocol.TypeCancelQueue:
+				if ticket != nil && roomManager.CancelQueue(ticket) {
+					ticket = nil
+					player.SendJSON(model.QueueCancelledMsg{Type: protocol.TypeQueueCancelled})
+				}
+
+			case protocol.TypeReadyRematch:
+				if room == nil {
+					continue
+				}
+				roomManager.HandleRematchReady(room, player.Index)
+
+			case protocol.TypeLeave:
+				if ticket != nil && roomManager.CancelQueue(ticket) {
+					ticket = nil
+				}
+				if spectating != nil {
+					roomManager.RemoveSpectator(spectating, player)
+					spectating = nil
+				}
+				if roomCode != "" {
+					roomManager.RemovePlayerFromRoom(roomCode, player.Index)
+					roomCode = ""
+					room = nil
+				}
+			}
 		}
 	}
 }
+
+// battleNickname applies the ranking nickname rules, since battle nicknames
+// are recorded in battle_ratings and battle_matches
+func battleNickname(player *model.Player, nickname string) (string, bool) {
+	nickname = strings.TrimSpace(nickname)
+	if nickname == "" || len(nickname) > 20 || !validNicknameRegex.MatchString(nickname) {
+		sendError(player, protocol.ErrInvalidNickname)
+		return "", false
+	}
+	return nickname, true
+}
+
+// sendError reports err to the player with its protocol error code
+func sendError(player *model.Player, err error) {
+	player.SendJSON(model.ErrorMsg{Type: protocol.TypeError, Code: protocol.CodeOf(err), Message: err.Error()})
+}
+
+// closeConn reports a protocol violation and closes the connection with code
+func closeConn(player *model.Player, conn *model.Conn, code int, err error) {
+	sendError(player, err)
+	conn.CloseWith(code, string(protocol.CodeOf(err)))
+}
@@ -8,6 +8,7 @@ import (
 	"mini-games/database"
 	"mini-games/handler"
 	"mini-games/middleware"
+	"mini-games/service"
 )
 
 func getEnv(key, fallback string) string {
@@ -21,12 +22,20 @@ func main() {
 	// Get port from environment variable
 	port := getEnv("PORT", "4001")
 
+	// Load the session token signing key (required)
+	if err := service.InitSessionSecret(); err != nil {
+		log.Fatal("Failed to load session secret:", err)
+	}
+
 	// Initialize database
 	if err := database.Init(); err != nil {
 		log.Fatal("Failed to initialize database:", err)
 	}
 	defer database.Close()
 
+	// Initialize game session store ("memory" or "sqlite")
+	service.InitSessionStore(getEnv("SESSION_STORE", "memory"))
+
 	// Initialize WebSocket handler
 	handler.InitWebSocket()
 
@@ -37,12 +46,22 @@ func main() {
 	mux.HandleFunc("/api/scores", handler.HandleScores)
 	mux.HandleFunc("/api/ranking", handler.HandleRanking)
 
-	// SpeedClick game API routes
-	mux.HandleFunc("/api/game/speedclick/start", handler.HandleSpeedClickStart)
-	mux.HandleFunc("/api/game/speedclick/click", handler.HandleSpeedClickClick)
-	mux.HandleFunc("/api/game/speedclick/miss", handler.HandleSpeedClickMiss)
-	mux.HandleFunc("/api/game/speedclick/end", handler.HandleSpeedClickEnd)
-	mux.HandleFunc("/api/game/speedclick/submit", handler.HandleSpeedClickSubmit)
+	// Server-validated game session API (/api/game/{game}/{action})
+	mux.HandleFunc("/api/game/", handler.HandleGame)
+
+	// Battle rating and match history
+	mux.HandleFunc("/api/battle/leaderboard", handler.HandleBattleLeaderboard)
+	mux.HandleFunc("/api/battle/history", handler.HandleBattleHistory)
+	mux.HandleFunc("/api/battle/replay", handler.HandleBattleReplay)
+	mux.HandleFunc("/api/battle/replay/", handler.HandleBattleReplay)
+	mux.HandleFunc("/api/battle/rooms", handler.HandleBattleRooms)
+
+	// Tournament brackets (생성은 관리자 전용)
+	mux.HandleFunc("/api/tournaments", handler.HandleTournaments)
+	mux.HandleFunc("/api/tournaments/", handler.HandleTournaments)
+
+	// Admin API routes
+	mux.HandleFunc("/api/admin/speedclick/verify", handler.HandleSpeedClickVerify)
 
 	// Apply middleware to API routes (order: Logging -> CORS -> RateLimit)
 	apiHandler := middleware.Logging(middleware.CORS(middleware.RateLimit(mux)))
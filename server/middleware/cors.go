@@ -43,7 +43,8 @@ func CORS(next http.Handler) http.Handler {
 		}
 
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Session-Token")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Session-Token")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
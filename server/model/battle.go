@@ -0,0 +1,119 @@
+package model
+
+import (
+	"encoding/json"
+	"math"
+	"sync"
+	"time"
+)
+
+// BattleMatch is a finished SpeedClick battle
+type BattleMatch struct {
+	ID            int64      `json:"id"`
+	RoomCode      string     `json:"roomCode"`
+	Nicknames     [2]string  `json:"nicknames"`
+	Scores        [2]int     `json:"scores"`
+	Winner        int        `json:"winner"`   // 0, 1, -1: 무승부
+	Rounds        int        `json:"rounds"`   // 시리즈면 점수는 라운드 승수
+	Duration      float64    `json:"duration"` // 초 (전체 라운드 합계)
+	BallCount     int        `json:"ballCount"`
+	RatingsBefore [2]float64 `json:"ratingsBefore"`
+	RatingsAfter  [2]float64 `json:"ratingsAfter"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// BattleRating is a player's head-to-head rating
+type BattleRating struct {
+	Nickname  string    `json:"nickname"`
+	Rating    float64   `json:"rating"`
+	Games     int       `json:"games"`
+	Wins      int       `json:"wins"`
+	Losses    int       `json:"losses"`
+	Draws     int       `json:"draws"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BattleReplayVersion is bumped whenever the event layout changes
+const BattleReplayVersion = 1
+
+// 리플레이 하나에 담을 최대 이벤트 수
+const MaxBattleReplayEvents = 20000
+
+// Battle replay event kinds. Every event is an array starting with the
+// milliseconds since the series started and the kind:
+//
+//	[t, "g", round]                                            라운드 시작
+//	[t, "s", ballId, x, y, isRed, size, timeLimitMs]           공 생성
+//	[t, "c", player, ballId, reactionMs, elapsedMs, rttMs, ok] 클릭 (ok: "" 또는 거부 사유)
+//	[t, "r", ballId, clickedBy, scores]                        공 판정 (-1: 아무도 안 누름)
+//	[t, "t", timeLeftMs]                                       남은 시간
+//	[t, "e", round, scores]                                    라운드 종료
+const (
+	BattleEventRound    = "g"
+	BattleEventSpawn    = "s"
+	BattleEventClick    = "c"
+	BattleEventResult   = "r"
+	BattleEventTime     = "t"
+	BattleEventRoundEnd = "e"
+)
+
+// BattleReplay is the recorded event stream of a battle series
+type BattleReplay struct {
+	Version   int             `json:"version"`
+	RoomCode  string          `json:"roomCode"`
+	MatchID   int64           `json:"matchId,omitempty"` // 레이팅이 기록된 1:1 대전만
+	Nicknames []string        `json:"nicknames"`         // 슬롯 순서
+	Rules     RoomRules       `json:"rules"`
+	Events    [][]interface{} `json:"events"`
+	Truncated bool            `json:"truncated,omitempty"` // MaxBattleReplayEvents 초과
+
+	start time.Time
+	mu    sync.Mutex
+}
+
+// NewBattleReplay starts recording a series between the given slots
+func NewBattleReplay(roomCode string, nicknames []string, rules RoomRules) *BattleReplay {
+	return &BattleReplay{
+		Version:   BattleReplayVersion,
+		RoomCode:  roomCode,
+		Nicknames: nicknames,
+		Rules:     rules,
+		Events:    [][]interface{}{},
+		start:     time.Now(),
+	}
+}
+
+// Record appends an event. Recording into a nil replay is a no-op.
+func (r *BattleReplay) Record(kind string, fields ...interface{}) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.Events) >= MaxBattleReplayEvents {
+		r.Truncated = true
+		return
+	}
+	event := make([]interface{}, 0, len(fields)+2)
+	event = append(event, time.Since(r.start).Milliseconds(), kind)
+	event = append(event, fields...)
+	r.Events = append(r.Events, event)
+}
+
+// Encode returns the replay as JSON
+func (r *BattleReplay) Encode() ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return json.Marshal(r)
+}
+
+// ReplayCoord rounds a game coordinate to one decimal to keep replays small
+func ReplayCoord(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+// ReplayMillis converts seconds to whole milliseconds
+func ReplayMillis(seconds float64) int64 {
+	return int64(math.Round(seconds * 1000))
+}
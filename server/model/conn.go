@@ -0,0 +1,146 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"mini-games/protocol"
+)
+
+const (
+	// 연결당 전송 대기열 크기 (프레임)
+	SendQueueSize = 64
+
+	// 프레임 하나를 쓰는 데 허용하는 시간
+	WriteTimeout = 5 * time.Second
+)
+
+var (
+	ErrConnClosed   = errors.New("Connection closed")
+	ErrSlowConsumer = errors.New("Send queue full")
+)
+
+// frame is an encoded message waiting in a connection's send queue
+type frame struct {
+	kind int
+	data []byte
+}
+
+// Conn owns all writes to a WebSocket connection. Messages are encoded by
+// the sender and queued; a writer goroutine sends them with a deadline, so
+// room logic never blocks on a slow client.
+//
+// Slow consumers: time_update is dropped once the queue is half full, and
+// a full queue closes the connection (the player then goes through the
+// usual reconnect grace period).
+type Conn struct {
+	ws      *websocket.Conn
+	binary  bool // 바이너리 서브프로토콜 협상됨
+	queue   chan frame
+	closing chan struct{}
+	once    sync.Once
+}
+
+// NewConn wraps ws and starts its writer goroutine
+func NewConn(ws *websocket.Conn) *Conn {
+	c := &Conn{
+		ws:      ws,
+		binary:  ws.Subprotocol() == protocol.SubprotocolBinary,
+		queue:   make(chan frame, SendQueueSize),
+		closing: make(chan struct{}),
+	}
+	go c.writePump()
+	return c
+}
+
+// Send encodes v and queues it. Messages with a binary layout go out as
+// binary frames if the connection negotiated the binary subprotocol.
+func (c *Conn) Send(v interface{}) error {
+	var f frame
+	if msg, ok := v.(protocol.BinaryMessage); ok && c.binary {
+		f = frame{websocket.BinaryMessage, msg.AppendFrame(nil)}
+	} else {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		f = frame{websocket.TextMessage, data}
+	}
+
+	select {
+	case <-c.closing:
+		return ErrConnClosed
+	default:
+	}
+
+	// 밀려 있으면 남은 시간 갱신은 버림 (다음 갱신이 곧 옴)
+	if _, ok := v.(TimeUpdateMsg); ok && len(c.queue) >= cap(c.queue)/2 {
+		return nil
+	}
+
+	select {
+	case c.queue <- f:
+		return nil
+	default:
+		log.Printf("Closing slow WebSocket client %s", c.ws.RemoteAddr())
+		c.once.Do(func() { close(c.closing) })
+		c.ws.Close()
+		return ErrSlowConsumer
+	}
+}
+
+// CloseWith queues a close frame after any pending messages and closes the
+// connection once it is written
+func (c *Conn) CloseWith(code int, reason string) {
+	select {
+	case c.queue <- frame{websocket.CloseMessage, websocket.FormatCloseMessage(code, reason)}:
+	default:
+	}
+	c.Close()
+}
+
+// Close flushes queued messages (bounded by WriteTimeout) and closes the
+// connection. Safe to call more than once.
+func (c *Conn) Close() {
+	c.once.Do(func() { close(c.closing) })
+}
+
+func (c *Conn) writePump() {
+	defer c.ws.Close()
+	for {
+		select {
+		case f := <-c.queue:
+			c.ws.SetWriteDeadline(time.Now().Add(WriteTimeout))
+			if !c.write(f) {
+				c.Close()
+				return
+			}
+		case <-c.closing:
+			// 남은 메시지를 한 번의 기한 안에 보내고 종료
+			c.ws.SetWriteDeadline(time.Now().Add(WriteTimeout))
+			for {
+				select {
+				case f := <-c.queue:
+					if !c.write(f) {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// write sends one frame; false means the connection is done
+func (c *Conn) write(f frame) bool {
+	if err := c.ws.WriteMessage(f.kind, f.data); err != nil {
+		return false
+	}
+	return f.kind != websocket.CloseMessage
+}
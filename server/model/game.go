@@ -6,16 +6,24 @@ import (
 
 // GameSession represents an active game session
 type GameSession struct {
-	ID           string        `json:"id"`
-	Game         string        `json:"game"`
-	Seed         int64         `json:"seed"`
-	StartTime    time.Time     `json:"start_time"`
-	Score        int           `json:"score"`
-	Lives        int           `json:"lives"`
-	CurrentBall  int           `json:"current_ball"`  // 현재 공 인덱스
-	BallSpawnTime int64        `json:"ball_spawn_time"` // 현재 공 생성 시간 (ms)
-	Clicks       []ClickRecord `json:"clicks"`
-	Status       string        `json:"status"` // "playing", "ended", "submitted"
+	ID        string      `json:"id"`
+	Game      string      `json:"game"`
+	Seed      int64       `json:"seed"`
+	Level     string      `json:"level,omitempty"` // 난이도 (snake 등)
+	StartTime time.Time   `json:"start_time"`
+	Score     int         `json:"score"`
+	Status    string      `json:"status"` // "playing", "ended", "submitted", "rejected"
+	State     interface{} `json:"-"`      // 게임별 상태 (*SpeedClickState 등)
+}
+
+// SpeedClickState holds the in-progress state of a SpeedClick session
+type SpeedClickState struct {
+	Lives         int           `json:"lives"`
+	CurrentBall   int           `json:"current_ball"`    // 현재 공 인덱스
+	BallSpawnTime int64         `json:"ball_spawn_time"` // 현재 공 생성 시간 (ms)
+	Clicks        []ClickRecord `json:"clicks"`
+	Misses        []int         `json:"misses"` // 놓친 공 인덱스
+	Hash          string        `json:"hash"`   // 이벤트 해시 체인
 }
 
 // ClickRecord represents a single click during a game
@@ -26,6 +34,23 @@ type ClickRecord struct {
 	Points    int   `json:"points"`
 }
 
+// SpeedClickReplay is the stored event log of a SpeedClick game
+type SpeedClickReplay struct {
+	Seed   int64         `json:"seed"`
+	Clicks []ClickRecord `json:"clicks"`
+	Misses []int         `json:"misses"`
+}
+
+// ReplayVerification is the result of re-verifying a stored score
+type ReplayVerification struct {
+	ScoreID     int64  `json:"scoreId"`
+	Nickname    string `json:"nickname"`
+	StoredScore int    `json:"storedScore"`
+	ReplayScore int    `json:"replayScore"`
+	Valid       bool   `json:"valid"`
+	Error       string `json:"error,omitempty"`
+}
+
 // SpeedClickBall represents a ball in SpeedClick game
 type SpeedClickBall struct {
 	Index     int     `json:"index"`
@@ -101,6 +126,15 @@ type SubmitScoreRequest struct {
 	Nickname  string `json:"nickname"`
 }
 
+// SpeedClickSubmitRequest is the request for submitting a SpeedClick score
+// with the event log that produced it
+type SpeedClickSubmitRequest struct {
+	SessionID string        `json:"sessionId"`
+	Nickname  string        `json:"nickname"`
+	Clicks    []ClickRecord `json:"clicks"`
+	Misses    []int         `json:"misses"`
+}
+
 // SubmitScoreResponse is the response for score submission
 type SubmitScoreResponse struct {
 	Success bool  `json:"success"`
@@ -0,0 +1,36 @@
+package model
+
+// JumpRunnerObstacle represents an obstacle in JumpRunner
+type JumpRunnerObstacle struct {
+	X      float64 `json:"x"`
+	Height float64 `json:"height"`
+}
+
+// JumpRunnerResult is the outcome of a server-side simulation
+type JumpRunnerResult struct {
+	Score    int     `json:"score"`    // 생존 프레임 수 (표시 점수 = score / 10)
+	Distance float64 `json:"distance"` // 이동 거리 (px)
+	Frames   int     `json:"frames"`
+}
+
+// JumpRunnerStartResponse is the response for JumpRunner game start
+type JumpRunnerStartResponse struct {
+	SessionID string `json:"sessionId"`
+	Seed      int64  `json:"seed"`
+	StartTime int64  `json:"startTime"`
+}
+
+// JumpRunnerFinishRequest is the request for finishing a JumpRunner game
+type JumpRunnerFinishRequest struct {
+	SessionID string `json:"sessionId"`
+	Jumps     []int  `json:"jumps"`  // 점프 입력 프레임 번호
+	Frames    int    `json:"frames"` // 충돌한 프레임 (클라이언트 기준)
+}
+
+// JumpRunnerFinishResponse is the response for JumpRunner game finish
+type JumpRunnerFinishResponse struct {
+	FinalScore int     `json:"finalScore"`
+	Distance   float64 `json:"distance"`
+	CanSubmit  bool    `json:"canSubmit"`
+	Message    string  `json:"message,omitempty"`
+}
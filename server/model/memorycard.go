@@ -0,0 +1,57 @@
+package model
+
+// MemoryCardLevel configuration
+type MemoryCardLevel struct {
+	Cols      int
+	Rows      int
+	Pairs     int
+	TimeLimit int // seconds
+	Bonus     int
+}
+
+// MemoryCardState holds the server-side deck of a MemoryCard session
+type MemoryCardState struct {
+	Faces    []int  `json:"faces"`   // 카드 ID별 그림 인덱스 (서버만 알고 있음)
+	Matched  []bool `json:"matched"` // 카드 ID별 매칭 여부
+	Flipped  int    `json:"flipped"` // 현재 뒤집혀 있는 카드 ID (-1: 없음)
+	Pairs    int    `json:"pairs"`   // 찾은 쌍 수
+	Moves    int    `json:"moves"`
+	Combo    int    `json:"combo"` // 연속 매칭 횟수
+	MaxCombo int    `json:"max_combo"`
+	ComboSum int    `json:"combo_sum"` // 콤보 보너스 누적
+}
+
+// MemoryCardStartRequest is the request for starting a MemoryCard game
+type MemoryCardStartRequest struct {
+	Level string `json:"level"` // "easy", "medium", "hard"
+}
+
+// MemoryCardStartResponse is the response for MemoryCard game start
+type MemoryCardStartResponse struct {
+	SessionID string `json:"sessionId"`
+	Cards     []int  `json:"cards"` // 카드 ID 목록 (그림은 flip으로만 공개)
+	Cols      int    `json:"cols"`
+	Rows      int    `json:"rows"`
+	TimeLimit int    `json:"timeLimit"`
+	StartTime int64  `json:"startTime"`
+}
+
+// MemoryCardFlipRequest is the request for flipping a card
+type MemoryCardFlipRequest struct {
+	SessionID string `json:"sessionId"`
+	CardID    int    `json:"cardId"`
+}
+
+// MemoryCardFlipResponse is the response for a flip
+type MemoryCardFlipResponse struct {
+	Valid      bool   `json:"valid"`
+	Face       int    `json:"face"`
+	Matched    bool   `json:"matched"`
+	PairCardID *int   `json:"pairCardId,omitempty"` // 두 번째 카드일 때 첫 번째 카드 ID
+	Moves      int    `json:"moves"`
+	Combo      int    `json:"combo"`
+	GameOver   bool   `json:"gameOver"`
+	Won        bool   `json:"won"`
+	FinalScore int    `json:"finalScore"`
+	Message    string `json:"message,omitempty"`
+}
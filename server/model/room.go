@@ -4,28 +4,99 @@ import (
 	"sync"
 	"time"
 
-	"github.com/gorilla/websocket"
+	"mini-games/protocol"
 )
 
+// RTTSampleCount is how many recent round-trip samples a player keeps
+const RTTSampleCount = 8
+
 // Player represents a player in a battle room
 type Player struct {
-	Conn       *websocket.Conn
+	Conn       *Conn
 	Nickname   string
 	Score      int
 	Ready      bool // Ready for rematch
 	Index      int  // 0 or 1
 	LastActive time.Time
+
+	ReconnectToken string    // rejoin 시 본인 확인용 토큰
+	Disconnected   bool      // 연결이 끊겨 재접속 대기 중
+	DisconnectedAt time.Time // 연결이 끊긴 시각
+
+	CheatScore int // 거부된 클릭 누적 점수
+
+	Bot     bool              // 서버가 조종하는 AI 플레이어
+	Deliver func(interface{}) // 설정되면 연결 대신 메시지를 전달 (봇)
+
+	ChatWindowStart time.Time // 채팅 속도 제한 구간 시작
+	ChatCount       int       // 구간 내 보낸 채팅/이모트 수
+
+	rttMu      sync.Mutex
+	rttSamples [RTTSampleCount]time.Duration // 최근 왕복 지연 표본 (순환)
+	rttCount   int                           // 지금까지 받은 표본 수
 	mu         sync.Mutex
 }
 
-// SendJSON sends a JSON message to the player
-func (p *Player) SendJSON(v interface{}) error {
+// Attach replaces the player's connection (used on rejoin)
+func (p *Player) Attach(conn *Conn) *Conn {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	old := p.Conn
+	p.Conn = conn
+	return old
+}
+
+// Detach clears the connection if it is still conn; returns false if the
+// player has already been re-attached to another connection
+func (p *Player) Detach(conn *Conn) bool {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	if p.Conn == nil {
+	if p.Conn != conn {
+		return false
+	}
+	p.Conn = nil
+	return true
+}
+
+// ObserveRTT records a round-trip sample, keeping the last RTTSampleCount
+func (p *Player) ObserveRTT(sample time.Duration) {
+	p.rttMu.Lock()
+	defer p.rttMu.Unlock()
+	p.rttSamples[p.rttCount%RTTSampleCount] = sample
+	p.rttCount++
+}
+
+// RTT returns the smallest of the player's recent round-trip samples (0 until
+// measured). The minimum can't be inflated by delaying a few pongs.
+func (p *Player) RTT() time.Duration {
+	p.rttMu.Lock()
+	defer p.rttMu.Unlock()
+	n := p.rttCount
+	if n > RTTSampleCount {
+		n = RTTSampleCount
+	}
+	var rtt time.Duration
+	for i := 0; i < n; i++ {
+		if i == 0 || p.rttSamples[i] < rtt {
+			rtt = p.rttSamples[i]
+		}
+	}
+	return rtt
+}
+
+// SendJSON queues a message on the player's connection (see Conn.Send)
+func (p *Player) SendJSON(v interface{}) error {
+	if p.Deliver != nil {
+		p.Deliver(v)
+		return nil
+	}
+	p.mu.Lock()
+	conn := p.Conn
+	p.mu.Unlock()
+	if conn == nil {
 		return nil
 	}
-	return p.Conn.WriteJSON(v)
+	return conn.Send(v)
 }
 
 // Ball represents a ball in the game
@@ -39,20 +110,45 @@ type Ball struct {
 	SpawnedAt time.Time
 	Clicked   bool
 	ClickedBy int // -1: none, 0: player1, 1: player2
+
+	Claims []ClickClaim // 판정 대기 중인 클릭
+}
+
+// ClickClaim is a validated click waiting for arbitration
+type ClickClaim struct {
+	PlayerIndex int
+	Reaction    time.Duration // 지연 보정된 반응 시간
 }
 
 // Room represents a battle room
 type Room struct {
 	Code      string
-	Players   [2]*Player
+	Players   []*Player // 슬롯 (nil: 빈 자리), len == Capacity
+	Capacity  int
+	Host      int // 방장 슬롯
 	State     RoomState
 	CreatedAt time.Time
 	GameStart time.Time
 	GameEnd   time.Time
 
-	CurrentBall *Ball
+	Balls       []*Ball // 살아 있는 공 (Rules.Balls개까지)
 	BallCounter int
 	Duration    float64 // Game duration in seconds
+	Rules       RoomRules
+
+	// Best-of-N 시리즈 진행 상황
+	Round          int   // 현재 라운드 (1부터)
+	RoundWins      []int // 슬롯별 라운드 승수
+	SeriesDuration float64
+	SeriesBalls    int
+
+	Spectators []*Player // 관전자 (읽기 전용 연결)
+
+	Replay *BattleReplay // 현재 시리즈 기록
+
+	Public          bool  // 공개 로비에 표시
+	TournamentID    int64 // 토너먼트 경기 방이면 설정
+	TournamentMatch int
 
 	Mu       sync.RWMutex
 	StopGame chan struct{}
@@ -62,10 +158,11 @@ type Room struct {
 type RoomState string
 
 const (
-	StateWaiting   RoomState = "waiting"
-	StateCountdown RoomState = "countdown"
-	StatePlaying   RoomState = "playing"
-	StateFinished  RoomState = "finished"
+	StateWaiting      RoomState = "waiting"
+	StateCountdown    RoomState = "countdown"
+	StatePlaying      RoomState = "playing"
+	StateIntermission RoomState = "intermission" // 시리즈 라운드 사이
+	StateFinished     RoomState = "finished"
 )
 
 // GameConfig represents difficulty settings based on elapsed time
@@ -87,78 +184,285 @@ func GetGameConfig(elapsedSeconds float64) GameConfig {
 	return GameConfig{BallSize: 50, TimeLimit: 0.5, BlueChance: 0.25}
 }
 
-// WebSocket message types
-type WSMessage struct {
-	Type     string      `json:"type"`
-	Data     interface{} `json:"data,omitempty"`
-	RoomCode string      `json:"roomCode,omitempty"`
-	Nickname string      `json:"nickname,omitempty"`
-}
-
 // Server to client messages
 type RoomCreatedMsg struct {
-	Type     string `json:"type"`
-	RoomCode string `json:"roomCode"`
+	Type           protocol.MessageType `json:"type"`
+	RoomCode       string               `json:"roomCode"`
+	Capacity       int                  `json:"capacity"`
+	Rules          RoomRules            `json:"rules"`
+	ReconnectToken string               `json:"reconnectToken"`
+}
+
+// RoomJoinedMsg is sent to a player joining a room with more than two slots
+type RoomJoinedMsg struct {
+	Type           protocol.MessageType `json:"type"`
+	RoomCode       string               `json:"roomCode"`
+	PlayerIndex    int                  `json:"playerIndex"`
+	Capacity       int                  `json:"capacity"`
+	Host           int                  `json:"host"`
+	Nicknames      []string             `json:"nicknames"` // 슬롯 순서 (빈 자리는 "")
+	Rules          RoomRules            `json:"rules"`
+	ReconnectToken string               `json:"reconnectToken"`
 }
 
 type OpponentJoinedMsg struct {
-	Type     string `json:"type"`
-	Nickname string `json:"nickname"`
+	Type           protocol.MessageType `json:"type"`
+	Nickname       string               `json:"nickname"`
+	Rules          *RoomRules           `json:"rules,omitempty"` // 참가자에게만 전달
+	ReconnectToken string               `json:"reconnectToken,omitempty"`
 }
 
 type CountdownMsg struct {
-	Type  string `json:"type"`
-	Count int    `json:"count"`
+	Type  protocol.MessageType `json:"type"`
+	Count int                  `json:"count"`
 }
 
 type GameStartMsg struct {
-	Type     string  `json:"type"`
-	Duration float64 `json:"duration"`
+	Type     protocol.MessageType `json:"type"`
+	Duration float64              `json:"duration"`
+	Round    int                  `json:"round,omitempty"`  // 시리즈 모드에서만
+	BestOf   int                  `json:"bestOf,omitempty"` // 시리즈 모드에서만
+}
+
+// RoundEndMsg reports the result of one round of a best-of-N series
+type RoundEndMsg struct {
+	Type        protocol.MessageType `json:"type"`
+	Round       int                  `json:"round"`
+	BestOf      int                  `json:"bestOf"`
+	RoundWinner int                  `json:"roundWinner"` // 슬롯, -1: 무승부
+	Standings   []Standing           `json:"standings"`   // 이번 라운드 점수
+	RoundWins   []int                `json:"roundWins"`
+	NextRoundIn float64              `json:"nextRoundIn"` // 0이면 시리즈 종료
+}
+
+// SeriesEndMsg is the final result of a best-of-N series; standings are
+// ranked by round wins
+type SeriesEndMsg struct {
+	Type           protocol.MessageType `json:"type"`
+	BestOf         int                  `json:"bestOf"`
+	Rounds         int                  `json:"rounds"`
+	RoundWins      []int                `json:"roundWins"`
+	Standings      []Standing           `json:"standings"`
+	Placement      int                  `json:"placement,omitempty"` // 관전자에게는 없음
+	Result         string               `json:"result,omitempty"`
+	WinnerNickname string               `json:"winnerNickname,omitempty"`
+	Rating         float64              `json:"rating,omitempty"`
+	RatingDelta    float64              `json:"ratingDelta,omitempty"`
+	MatchID        int64                `json:"matchId,omitempty"`  // 레이팅이 기록된 1:1 대전만
+	ReplayID       int64                `json:"replayId,omitempty"` // 리플레이 조회용
 }
 
 type BallSpawnMsg struct {
-	Type      string  `json:"type"`
-	ID        int     `json:"id"`
-	X         float64 `json:"x"`
-	Y         float64 `json:"y"`
-	IsRed     bool    `json:"isRed"`
-	Size      int     `json:"size"`
-	TimeLimit float64 `json:"timeLimit"`
+	Type      protocol.MessageType `json:"type"`
+	ID        int                  `json:"id"`
+	X         float64              `json:"x"`
+	Y         float64              `json:"y"`
+	IsRed     bool                 `json:"isRed"`
+	Size      int                  `json:"size"`
+	TimeLimit float64              `json:"timeLimit"`
+}
+
+func (m BallSpawnMsg) AppendFrame(b []byte) []byte {
+	return protocol.AppendBallSpawn(b, m.ID, m.X, m.Y, m.IsRed, m.Size, m.TimeLimit)
 }
 
 type BallResultMsg struct {
-	Type      string `json:"type"`
-	BallID    int    `json:"ballId"`
-	ClickedBy string `json:"clickedBy"` // "player1", "player2", "none"
-	Scores    [2]int `json:"scores"`
+	Type      protocol.MessageType `json:"type"`
+	BallID    int                  `json:"ballId"`
+	ClickedBy string               `json:"clickedBy"` // "player1" ~ "player8", "none"
+	Scores    []int                `json:"scores"`    // 슬롯 순서
+
+	Slot int `json:"-"` // ClickedBy의 슬롯 번호, -1이면 없음 (바이너리 프레임용)
+}
+
+func (m BallResultMsg) AppendFrame(b []byte) []byte {
+	return protocol.AppendBallResult(b, m.BallID, m.Slot, m.Scores)
+}
+
+// Standing is a player's final placement in a game
+type Standing struct {
+	PlayerIndex int    `json:"playerIndex"`
+	Nickname    string `json:"nickname"`
+	Score       int    `json:"score"`
+	Place       int    `json:"place"` // 동점은 같은 순위
 }
 
 type GameEndMsg struct {
-	Type           string `json:"type"`
-	MyScore        int    `json:"myScore"`
-	OpponentScore  int    `json:"opponentScore"`
-	Result         string `json:"result"` // "win", "lose", "draw"
-	WinnerNickname string `json:"winnerNickname,omitempty"`
+	Type           protocol.MessageType `json:"type"`
+	MyScore        int                  `json:"myScore"`
+	OpponentScore  int                  `json:"opponentScore"`
+	Result         string               `json:"result"` // "win", "lose", "draw"
+	WinnerNickname string               `json:"winnerNickname,omitempty"`
+	Rating         float64              `json:"rating,omitempty"`      // 경기 후 레이팅
+	RatingDelta    float64              `json:"ratingDelta,omitempty"` // 레이팅 변동
+	Placement      int                  `json:"placement"`
+	Standings      []Standing           `json:"standings"`
+	MatchID        int64                `json:"matchId,omitempty"`  // 레이팅이 기록된 1:1 대전만
+	ReplayID       int64                `json:"replayId,omitempty"` // 리플레이 조회용
 }
 
 type OpponentReadyMsg struct {
-	Type string `json:"type"`
+	Type        protocol.MessageType `json:"type"`
+	PlayerIndex int                  `json:"playerIndex"`
 }
 
 type RematchStartMsg struct {
-	Type string `json:"type"`
+	Type protocol.MessageType `json:"type"`
 }
 
 type OpponentLeftMsg struct {
-	Type string `json:"type"`
+	Type protocol.MessageType `json:"type"`
+}
+
+type OpponentDisconnectedMsg struct {
+	Type        protocol.MessageType `json:"type"`
+	PlayerIndex int                  `json:"playerIndex"`
+	Grace       float64              `json:"grace"` // 재접속 대기 시간 (초)
+}
+
+type OpponentReconnectedMsg struct {
+	Type        protocol.MessageType `json:"type"`
+	PlayerIndex int                  `json:"playerIndex"`
+}
+
+type HostChangedMsg struct {
+	Type protocol.MessageType `json:"type"`
+	Host int                  `json:"host"`
+}
+
+// RoomSnapshot is the current state of a room, sent to rejoining players
+// and new spectators
+type RoomSnapshot struct {
+	RoomCode   string         `json:"roomCode"`
+	State      RoomState      `json:"state"`
+	Capacity   int            `json:"capacity"`
+	Host       int            `json:"host"`
+	Rules      RoomRules      `json:"rules"`
+	Nicknames  []string       `json:"nicknames"` // 슬롯 순서 (빈 자리는 "")
+	Scores     []int          `json:"scores"`
+	TimeLeft   float64        `json:"timeLeft"`
+	Balls      []BallSpawnMsg `json:"balls,omitempty"`
+	Spectators int            `json:"spectators"`
+	Round      int            `json:"round,omitempty"`
+	RoundWins  []int          `json:"roundWins,omitempty"`
+}
+
+// RejoinedMsg restores the client state after a reconnect
+type RejoinedMsg struct {
+	Type protocol.MessageType `json:"type"`
+	RoomSnapshot
+	PlayerIndex    int  `json:"playerIndex"`
+	OpponentOnline bool `json:"opponentOnline"`
+}
+
+// SpectatingMsg confirms a spectator attached to a room
+type SpectatingMsg struct {
+	Type protocol.MessageType `json:"type"`
+	RoomSnapshot
+}
+
+type SpectatorCountMsg struct {
+	Type  protocol.MessageType `json:"type"`
+	Count int                  `json:"count"`
+}
+
+// SpectatorGameEndMsg is the game_end sent to spectators (no "my" side)
+type SpectatorGameEndMsg struct {
+	Type           protocol.MessageType `json:"type"`
+	Standings      []Standing           `json:"standings"`
+	WinnerNickname string               `json:"winnerNickname,omitempty"`
+	ReplayID       int64                `json:"replayId,omitempty"`
 }
 
+type PlayerJoinedMsg struct {
+	Type        protocol.MessageType `json:"type"`
+	PlayerIndex int                  `json:"playerIndex"`
+	Nickname    string               `json:"nickname"`
+}
+
+type PlayerLeftMsg struct {
+	Type        protocol.MessageType `json:"type"`
+	PlayerIndex int                  `json:"playerIndex"`
+}
+
+type RoomClosedMsg struct {
+	Type protocol.MessageType `json:"type"`
+}
+
+type MatchFoundMsg struct {
+	Type           protocol.MessageType `json:"type"`
+	RoomCode       string               `json:"roomCode"`
+	PlayerIndex    int                  `json:"playerIndex"`
+	Nickname       string               `json:"nickname"` // 상대 닉네임
+	ReconnectToken string               `json:"reconnectToken"`
+}
+
+type QueueStatusMsg struct {
+	Type          protocol.MessageType `json:"type"`
+	QueueSize     int                  `json:"queueSize"`
+	Position      int                  `json:"position"`
+	Waited        float64              `json:"waited"`        // 초
+	EstimatedWait float64              `json:"estimatedWait"` // 초 (0이면 알 수 없음)
+}
+
+type QueueCancelledMsg struct {
+	Type protocol.MessageType `json:"type"`
+}
+
+// LobbyRoom is a public room as listed in the lobby
+type LobbyRoom struct {
+	RoomCode   string    `json:"roomCode"`
+	Host       string    `json:"host"` // 방장 닉네임
+	Players    int       `json:"players"`
+	Capacity   int       `json:"capacity"`
+	Rules      RoomRules `json:"rules"`
+	State      RoomState `json:"state"`
+	Spectators int       `json:"spectators"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type LobbyRoomsMsg struct {
+	Type  protocol.MessageType `json:"type"`
+	Rooms []LobbyRoom          `json:"rooms"`
+}
+
+type LobbyRoomMsg struct {
+	Type protocol.MessageType `json:"type"`
+	Room LobbyRoom            `json:"room"`
+}
+
+type LobbyRoomRemovedMsg struct {
+	Type     protocol.MessageType `json:"type"`
+	RoomCode string               `json:"roomCode"`
+}
+
+type ChatMsg struct {
+	Type        protocol.MessageType `json:"type"`
+	PlayerIndex int                  `json:"playerIndex"`
+	Nickname    string               `json:"nickname"`
+	Text        string               `json:"text"`
+}
+
+type EmoteMsg struct {
+	Type        protocol.MessageType `json:"type"`
+	PlayerIndex int                  `json:"playerIndex"`
+	Nickname    string               `json:"nickname"`
+	Emote       string               `json:"emote"`
+}
+
+// ErrorMsg reports a failed request; clients should branch on Code and
+// only display Message
 type ErrorMsg struct {
-	Type    string `json:"type"`
-	Message string `json:"message"`
+	Type    protocol.MessageType `json:"type"`
+	Code    protocol.ErrorCode   `json:"code"`
+	Message string               `json:"message"`
 }
 
 type TimeUpdateMsg struct {
-	Type        string  `json:"type"`
-	TimeLeft    float64 `json:"timeLeft"`
+	Type     protocol.MessageType `json:"type"`
+	TimeLeft float64              `json:"timeLeft"`
+}
+
+func (m TimeUpdateMsg) AppendFrame(b []byte) []byte {
+	return protocol.AppendTimeUpdate(b, m.TimeLeft)
 }
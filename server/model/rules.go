@@ -0,0 +1,61 @@
+package model
+
+// Difficulty presets
+const (
+	DifficultyEasy   = "easy"
+	DifficultyNormal = "normal"
+	DifficultyHard   = "hard"
+	DifficultyCustom = "custom"
+)
+
+// DifficultyStep is one segment of a difficulty curve
+type DifficultyStep struct {
+	Until      float64 `json:"until"` // 경과 시간(초) 기준 구간 끝, 0이면 끝까지
+	BallSize   int     `json:"ballSize"`
+	TimeLimit  float64 `json:"timeLimit"`
+	BlueChance float64 `json:"blueChance"`
+}
+
+// RoomRules are the options chosen when a battle room is created
+type RoomRules struct {
+	Duration    float64          `json:"duration"`        // 라운드 시간 (초)
+	Difficulty  string           `json:"difficulty"`      // easy, normal, hard, custom
+	Curve       []DifficultyStep `json:"curve,omitempty"` // custom일 때만 사용
+	BluePenalty int              `json:"bluePenalty"`     // 파란 공 클릭 시 감점
+	Balls       int              `json:"balls"`           // 동시에 나오는 공 수
+	BestOf      int              `json:"bestOf"`          // 라운드 수 (홀수)
+}
+
+// 프리셋 난이도 곡선 (normal은 GetGameConfig)
+var difficultyPresets = map[string][]DifficultyStep{
+	DifficultyEasy: {
+		{Until: 4, BallSize: 90, TimeLimit: 1.2, BlueChance: 0.05},
+		{Until: 8, BallSize: 80, TimeLimit: 1.0, BlueChance: 0.10},
+		{BallSize: 70, TimeLimit: 0.8, BlueChance: 0.15},
+	},
+	DifficultyHard: {
+		{Until: 3, BallSize: 60, TimeLimit: 0.7, BlueChance: 0.20},
+		{Until: 6, BallSize: 50, TimeLimit: 0.55, BlueChance: 0.25},
+		{BallSize: 40, TimeLimit: 0.45, BlueChance: 0.30},
+	},
+}
+
+// GameConfig returns the difficulty for the elapsed time under these rules
+func (r RoomRules) GameConfig(elapsedSeconds float64) GameConfig {
+	steps := difficultyPresets[r.Difficulty]
+	if r.Difficulty == DifficultyCustom {
+		steps = r.Curve
+	}
+	if len(steps) == 0 {
+		return GetGameConfig(elapsedSeconds)
+	}
+
+	step := steps[len(steps)-1]
+	for _, s := range steps {
+		if s.Until <= 0 || elapsedSeconds < s.Until {
+			step = s
+			break
+		}
+	}
+	return GameConfig{BallSize: step.BallSize, TimeLimit: step.TimeLimit, BlueChance: step.BlueChance}
+}
@@ -0,0 +1,50 @@
+package model
+
+// SnakeLevel configuration
+type SnakeLevel struct {
+	Speed         int // ms per tick
+	SpeedIncrease int // ms faster per food
+	MinSpeed      int // ms
+}
+
+// SnakeMove is a single direction change in the move log
+type SnakeMove struct {
+	Tick int    `json:"t"` // 방향이 적용되는 틱 번호 (0부터)
+	Dir  string `json:"d"` // "U", "D", "L", "R"
+}
+
+// SnakeResult is the outcome of a server-side simulation
+type SnakeResult struct {
+	Score      int   `json:"score"`
+	Length     int   `json:"length"`
+	Ticks      int   `json:"ticks"`
+	DurationMs int64 `json:"durationMs"` // 최소 플레이 시간
+}
+
+// SnakeStartRequest is the request for starting a snake game
+type SnakeStartRequest struct {
+	Level string `json:"level"` // "easy", "medium", "hard"
+}
+
+// SnakeStartResponse is the response for snake game start
+type SnakeStartResponse struct {
+	SessionID string `json:"sessionId"`
+	Seed      int64  `json:"seed"`
+	StartTime int64  `json:"startTime"`
+	Level     string `json:"level"`
+}
+
+// SnakeFinishRequest is the request for finishing a snake game
+type SnakeFinishRequest struct {
+	SessionID string      `json:"sessionId"`
+	Moves     []SnakeMove `json:"moves"`
+}
+
+// SnakeFinishResponse is the response for snake game finish
+type SnakeFinishResponse struct {
+	FinalScore int    `json:"finalScore"`
+	Length     int    `json:"length"`
+	Ticks      int    `json:"ticks"`
+	CanSubmit  bool   `json:"canSubmit"`
+	Message    string `json:"message,omitempty"`
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"time"
+
+	"mini-games/protocol"
+)
+
+// Tournament formats
+const (
+	TournamentSingle = "single" // 싱글 엘리미네이션
+	TournamentDouble = "double" // 더블 엘리미네이션 (패자조 우승자가 결승을 이기면 리셋 경기)
+)
+
+// Bracket sides
+const (
+	BracketWinners = "winners"
+	BracketLosers  = "losers"
+	BracketFinal   = "final"
+)
+
+// Tournament states
+const (
+	TournamentRunning  = "running"
+	TournamentFinished = "finished"
+)
+
+// Tournament match states
+const (
+	MatchWaiting = "waiting" // 상대가 정해지지 않음
+	MatchReady   = "ready"   // 두 선수 확정, 체크인 대기
+	MatchPlaying = "playing" // 방에서 진행 중
+	MatchDone    = "done"
+	MatchSkipped = "skipped" // 치를 필요가 없어진 결승 리셋
+)
+
+// Tournament is a bracket of SpeedClick battles
+type Tournament struct {
+	ID        int64              `json:"id"`
+	Name      string             `json:"name"`
+	Format    string             `json:"format"`
+	Entrants  []string           `json:"entrants"` // 시드 순
+	Rules     RoomRules          `json:"rules"`
+	State     string             `json:"state"`
+	Matches   []*TournamentMatch `json:"matches"`
+	Champion  string             `json:"champion,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+
+	// 참가자별 체크인 토큰 (생성 응답에만 포함)
+	CheckInTokens map[string]string `json:"checkInTokens,omitempty"`
+}
+
+// TournamentMatch is one pairing in a bracket. IDs are 1-based indexes
+// into Tournament.Matches.
+type TournamentMatch struct {
+	ID       int             `json:"id"`
+	Bracket  string          `json:"bracket"`
+	Round    int             `json:"round"`
+	Players  [2]string       `json:"players"` // "": 미정 또는 부전승
+	Scores   [2]int          `json:"scores"`
+	Winner   string          `json:"winner,omitempty"`
+	State    string          `json:"state"`
+	Bye      bool            `json:"bye,omitempty"` // 부전승으로 처리됨
+	RoomCode string          `json:"roomCode,omitempty"`
+	WinnerTo *TournamentSlot `json:"winnerTo,omitempty"` // nil이면 결승
+	LoserTo  *TournamentSlot `json:"loserTo,omitempty"`  // nil이면 탈락
+
+	Filled [2]bool `json:"-"` // 슬롯이 확정됨 (선수 또는 빈 자리)
+}
+
+// TournamentSlot points at a player slot of a later match
+type TournamentSlot struct {
+	Match int `json:"match"`
+	Slot  int `json:"slot"`
+}
+
+type TournamentCheckedInMsg struct {
+	Type         protocol.MessageType `json:"type"`
+	TournamentID int64                `json:"tournamentId"`
+	MatchID      int                  `json:"matchId"`
+	Opponent     string               `json:"opponent,omitempty"` // 상대가 아직 없으면 생략
+}
+
+type TournamentMatchMsg struct {
+	Type           protocol.MessageType `json:"type"`
+	TournamentID   int64                `json:"tournamentId"`
+	MatchID        int                  `json:"matchId"`
+	RoomCode       string               `json:"roomCode"`
+	PlayerIndex    int                  `json:"playerIndex"`
+	Opponent       string               `json:"opponent"`
+	ReconnectToken string               `json:"reconnectToken"`
+}
@@ -0,0 +1,85 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// WebSocket subprotocols. A client offering SubprotocolBinary receives
+// time_update, ball_spawn and ball_result as binary frames; everything
+// else (and every message without a subprotocol) stays JSON.
+const (
+	SubprotocolJSON   = "speedclick.v1.json"
+	SubprotocolBinary = "speedclick.v1.binary"
+)
+
+// 인코딩 이름 (hello 응답)
+const (
+	EncodingJSON   = "json"
+	EncodingBinary = "binary"
+)
+
+// Binary frame opcodes (first byte). All integers are big-endian.
+//
+//	time_update: op u8 | timeLeft u16 (ms)
+//	ball_spawn:  op u8 | id u32 | x f32 | y f32 | flags u8 (1: red) | size u16 | timeLimit u16 (ms)
+//	ball_result: op u8 | ballId u32 | clickedBy i8 (slot, -1: none) | n u8 | scores n×i32
+const (
+	OpTimeUpdate byte = 1
+	OpBallSpawn  byte = 2
+	OpBallResult byte = 3
+)
+
+// BinaryMessage is a server message with a binary layout
+type BinaryMessage interface {
+	AppendFrame(b []byte) []byte
+}
+
+// Encoding returns the encoding selected by a negotiated subprotocol
+func Encoding(subprotocol string) string {
+	if subprotocol == SubprotocolBinary {
+		return EncodingBinary
+	}
+	return EncodingJSON
+}
+
+func AppendTimeUpdate(b []byte, timeLeft float64) []byte {
+	b = append(b, OpTimeUpdate)
+	return binary.BigEndian.AppendUint16(b, millis16(timeLeft))
+}
+
+func AppendBallSpawn(b []byte, id int, x, y float64, isRed bool, size int, timeLimit float64) []byte {
+	b = append(b, OpBallSpawn)
+	b = binary.BigEndian.AppendUint32(b, uint32(id))
+	b = binary.BigEndian.AppendUint32(b, math.Float32bits(float32(x)))
+	b = binary.BigEndian.AppendUint32(b, math.Float32bits(float32(y)))
+	var flags byte
+	if isRed {
+		flags |= 1
+	}
+	b = append(b, flags)
+	b = binary.BigEndian.AppendUint16(b, uint16(size))
+	return binary.BigEndian.AppendUint16(b, millis16(timeLimit))
+}
+
+func AppendBallResult(b []byte, ballID int, clickedBy int, scores []int) []byte {
+	b = append(b, OpBallResult)
+	b = binary.BigEndian.AppendUint32(b, uint32(ballID))
+	b = append(b, byte(int8(clickedBy)), byte(len(scores)))
+	for _, score := range scores {
+		b = binary.BigEndian.AppendUint32(b, uint32(int32(score)))
+	}
+	return b
+}
+
+// millis16 converts seconds to milliseconds, clamped to a u16
+func millis16(seconds float64) uint16 {
+	ms := math.Round(seconds * 1000)
+	if ms < 0 {
+		return 0
+	}
+	if ms > math.MaxUint16 {
+		return math.MaxUint16
+	}
+	return uint16(ms)
+}
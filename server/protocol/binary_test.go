@@ -0,0 +1,163 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// 테스트용 디코더 (클라이언트와 같은 레이아웃으로 읽음)
+
+type ballSpawnFrame struct {
+	id        uint32
+	x, y      float32
+	red       bool
+	size      uint16
+	timeLimit uint16
+}
+
+type ballResultFrame struct {
+	ballID    uint32
+	clickedBy int8
+	scores    []int32
+}
+
+func decodeTimeUpdate(t *testing.T, b []byte) uint16 {
+	t.Helper()
+	if len(b) != 3 || b[0] != OpTimeUpdate {
+		t.Fatalf("time_update frame = %v", b)
+	}
+	return binary.BigEndian.Uint16(b[1:])
+}
+
+func decodeBallSpawn(t *testing.T, b []byte) ballSpawnFrame {
+	t.Helper()
+	if len(b) != 18 || b[0] != OpBallSpawn {
+		t.Fatalf("ball_spawn frame = %v", b)
+	}
+	return ballSpawnFrame{
+		id:        binary.BigEndian.Uint32(b[1:]),
+		x:         math.Float32frombits(binary.BigEndian.Uint32(b[5:])),
+		y:         math.Float32frombits(binary.BigEndian.Uint32(b[9:])),
+		red:       b[13]&1 != 0,
+		size:      binary.BigEndian.Uint16(b[14:]),
+		timeLimit: binary.BigEndian.Uint16(b[16:]),
+	}
+}
+
+func decodeBallResult(t *testing.T, b []byte) ballResultFrame {
+	t.Helper()
+	if len(b) < 7 || b[0] != OpBallResult {
+		t.Fatalf("ball_result frame = %v", b)
+	}
+	n := int(b[6])
+	if len(b) != 7+4*n {
+		t.Fatalf("ball_result frame has %d bytes for %d scores", len(b), n)
+	}
+	frame := ballResultFrame{
+		ballID:    binary.BigEndian.Uint32(b[1:]),
+		clickedBy: int8(b[5]),
+		scores:    make([]int32, n),
+	}
+	for i := range frame.scores {
+		frame.scores[i] = int32(binary.BigEndian.Uint32(b[7+4*i:]))
+	}
+	return frame
+}
+
+func TestTimeUpdateRoundTrip(t *testing.T) {
+	tests := []struct {
+		timeLeft float64
+		want     uint16
+	}{
+		{10, 10000},
+		{9.9876, 9988},
+		{0, 0},
+		{-0.5, 0},                // 음수는 0으로
+		{120, math.MaxUint16},    // u16 범위를 넘으면 최댓값
+		{65.535, math.MaxUint16}, // 정확히 최댓값
+	}
+
+	for _, tt := range tests {
+		if got := decodeTimeUpdate(t, AppendTimeUpdate(nil, tt.timeLeft)); got != tt.want {
+			t.Errorf("timeLeft %v: decoded %d, want %d", tt.timeLeft, got, tt.want)
+		}
+	}
+}
+
+func TestBallSpawnRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        int
+		x, y      float64
+		isRed     bool
+		size      int
+		timeLimit float64
+	}{
+		{"red ball", 1, 600, 400, true, 80, 1.5},
+		{"blue ball", 42, 85.25, 712.5, false, 50, 0.4},
+		{"large id", 1 << 30, 0, 0, true, 150, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeBallSpawn(t, AppendBallSpawn(nil, tt.id, tt.x, tt.y, tt.isRed, tt.size, tt.timeLimit))
+			want := ballSpawnFrame{
+				id:        uint32(tt.id),
+				x:         float32(tt.x),
+				y:         float32(tt.y),
+				red:       tt.isRed,
+				size:      uint16(tt.size),
+				timeLimit: uint16(math.Round(tt.timeLimit * 1000)),
+			}
+			if got != want {
+				t.Errorf("decoded %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestBallResultRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		ballID    int
+		clickedBy int
+		scores    []int
+	}{
+		{"clicked by first player", 3, 0, []int{1, 0}},
+		{"nobody clicked", 9, -1, []int{4, 5}},
+		{"negative scores", 12, 1, []int{-3, 2}},
+		{"four players", 100, 3, []int{7, -1, 0, 12}},
+		{"no scores", 1, -1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeBallResult(t, AppendBallResult(nil, tt.ballID, tt.clickedBy, tt.scores))
+			if got.ballID != uint32(tt.ballID) || int(got.clickedBy) != tt.clickedBy {
+				t.Errorf("decoded ball %d clicked by %d, want %d by %d", got.ballID, got.clickedBy, tt.ballID, tt.clickedBy)
+			}
+			if len(got.scores) != len(tt.scores) {
+				t.Fatalf("decoded %d scores, want %d", len(got.scores), len(tt.scores))
+			}
+			for i, score := range tt.scores {
+				if int(got.scores[i]) != score {
+					t.Errorf("scores[%d] = %d, want %d", i, got.scores[i], score)
+				}
+			}
+		})
+	}
+}
+
+func TestAppendFramesShareBuffer(t *testing.T) {
+	// 여러 프레임을 한 버퍼에 이어 붙여도 각 프레임은 독립적으로 읽혀야 함
+	b := AppendTimeUpdate(nil, 5)
+	b = AppendBallResult(b, 2, 1, []int{3, 4})
+
+	if got := decodeTimeUpdate(t, b[:3]); got != 5000 {
+		t.Errorf("time_update = %d, want 5000", got)
+	}
+	if got := decodeBallResult(t, b[3:]); got.ballID != 2 || got.clickedBy != 1 {
+		t.Errorf("ball_result = %+v", got)
+	}
+}
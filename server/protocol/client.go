@@ -0,0 +1,221 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+)
+
+// Message is a decoded client message
+type Message interface {
+	MessageType() MessageType
+	Validate() error
+}
+
+// Header is the envelope shared by every message
+type Header struct {
+	Type MessageType `json:"type"`
+}
+
+func (h Header) MessageType() MessageType {
+	return h.Type
+}
+
+// Hello opens a connection. A client that starts with any other message
+// is treated as protocol version 1.
+type Hello struct {
+	Header
+	Version      int      `json:"version"`
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+func (m *Hello) Validate() error {
+	if m.Version < MinVersion || m.Version > Version {
+		return ErrUnsupportedVersion
+	}
+	return nil
+}
+
+type Create struct {
+	Header
+	Nickname string          `json:"nickname"`
+	Capacity int             `json:"capacity,omitempty"` // 0: 기본 인원
+	Rules    json.RawMessage `json:"rules,omitempty"`
+	Public   bool            `json:"public,omitempty"`
+}
+
+func (m *Create) Validate() error {
+	return requireNickname(m.Nickname)
+}
+
+type PlayBot struct {
+	Header
+	Nickname   string          `json:"nickname"`
+	Difficulty string          `json:"difficulty,omitempty"` // "": normal
+	Rules      json.RawMessage `json:"rules,omitempty"`
+}
+
+func (m *PlayBot) Validate() error {
+	return requireNickname(m.Nickname)
+}
+
+type Join struct {
+	Header
+	Nickname string `json:"nickname"`
+	RoomCode string `json:"roomCode"`
+}
+
+func (m *Join) Validate() error {
+	if err := requireNickname(m.Nickname); err != nil {
+		return err
+	}
+	return requireRoomCode(m.RoomCode)
+}
+
+type Rejoin struct {
+	Header
+	RoomCode       string `json:"roomCode"`
+	ReconnectToken string `json:"reconnectToken"`
+}
+
+func (m *Rejoin) Validate() error {
+	return requireRoomCode(m.RoomCode)
+}
+
+type Spectate struct {
+	Header
+	RoomCode string `json:"roomCode"`
+	Nickname string `json:"nickname,omitempty"`
+}
+
+func (m *Spectate) Validate() error {
+	return requireRoomCode(m.RoomCode)
+}
+
+type Queue struct {
+	Header
+	Nickname string `json:"nickname"`
+	Rated    bool   `json:"rated,omitempty"`
+}
+
+func (m *Queue) Validate() error {
+	return requireNickname(m.Nickname)
+}
+
+type TournamentCheckIn struct {
+	Header
+	TournamentID int64  `json:"tournamentId"`
+	Nickname     string `json:"nickname"`
+	CheckInToken string `json:"checkInToken"` // 토너먼트 생성 시 참가자별로 발급
+}
+
+func (m *TournamentCheckIn) Validate() error {
+	return requireNickname(m.Nickname)
+}
+
+type Click struct {
+	Header
+	BallID  int      `json:"ballId"`
+	X       *float64 `json:"x"`
+	Y       *float64 `json:"y"`
+	Elapsed *float64 `json:"elapsed,omitempty"` // 클라이언트가 잰 반응 시간 (초)
+}
+
+func (m *Click) Validate() error {
+	return nil // 좌표와 공 검증은 서비스에서 처리
+}
+
+type Chat struct {
+	Header
+	Text string `json:"text"`
+}
+
+func (m *Chat) Validate() error {
+	text := strings.TrimSpace(m.Text)
+	if text == "" {
+		return ErrChatEmpty
+	}
+	if utf8.RuneCountInString(text) > MaxChatLength {
+		return ErrChatTooLong
+	}
+	return nil
+}
+
+type Emote struct {
+	Header
+	Emote string `json:"emote"`
+}
+
+func (m *Emote) Validate() error {
+	return nil
+}
+
+// Empty is a message with no fields (start, leave, ...)
+type Empty struct {
+	Header
+}
+
+func (m *Empty) Validate() error {
+	return nil
+}
+
+// 메시지 타입별 생성자
+var registry = map[MessageType]func() Message{
+	TypeHello:             func() Message { return &Hello{} },
+	TypeCreate:            func() Message { return &Create{} },
+	TypePlayBot:           func() Message { return &PlayBot{} },
+	TypeJoin:              func() Message { return &Join{} },
+	TypeStart:             func() Message { return &Empty{} },
+	TypeRejoin:            func() Message { return &Rejoin{} },
+	TypeSpectate:          func() Message { return &Spectate{} },
+	TypeQueue:             func() Message { return &Queue{} },
+	TypeTournamentCheckIn: func() Message { return &TournamentCheckIn{} },
+	TypeLobbySubscribe:    func() Message { return &Empty{} },
+	TypeLobbyUnsubscribe:  func() Message { return &Empty{} },
+	TypeCancelQueue:       func() Message { return &Empty{} },
+	TypeClick:             func() Message { return &Click{} },
+	TypeChat:              func() Message { return &Chat{} },
+	TypeEmote:             func() Message { return &Emote{} },
+	TypeReadyRematch:      func() Message { return &Empty{} },
+	TypeLeave:             func() Message { return &Empty{} },
+}
+
+// Decode parses and validates one client frame. Unknown fields or wrongly
+// typed values are ErrInvalidMessage; broken JSON and unknown types are
+// errors that close the connection (see CloseCode).
+func Decode(data []byte) (Message, error) {
+	var header Header
+	if err := json.Unmarshal(data, &header); err != nil || header.Type == "" {
+		return nil, ErrMalformedMessage
+	}
+	newMessage, ok := registry[header.Type]
+	if !ok {
+		return nil, ErrUnknownType
+	}
+
+	msg := newMessage()
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(msg); err != nil {
+		return nil, ErrInvalidMessage
+	}
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func requireNickname(nickname string) error {
+	if nickname == "" {
+		return ErrNicknameRequired
+	}
+	return nil
+}
+
+func requireRoomCode(roomCode string) error {
+	if roomCode == "" {
+		return ErrRoomCodeRequired
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package protocol
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantType MessageType
+		wantErr  error
+	}{
+		{"hello", `{"type":"hello","version":1}`, TypeHello, nil},
+		{"hello with capabilities", `{"type":"hello","version":1,"capabilities":["binary"]}`, TypeHello, nil},
+		{"create", `{"type":"create","nickname":"kim","capacity":4,"rules":{"balls":2},"public":true}`, TypeCreate, nil},
+		{"play bot", `{"type":"play_bot","nickname":"kim","difficulty":"hard"}`, TypePlayBot, nil},
+		{"join", `{"type":"join","nickname":"kim","roomCode":"ABC123"}`, TypeJoin, nil},
+		{"rejoin", `{"type":"rejoin","roomCode":"ABC123","reconnectToken":"t"}`, TypeRejoin, nil},
+		{"spectate without nickname", `{"type":"spectate","roomCode":"ABC123"}`, TypeSpectate, nil},
+		{"queue", `{"type":"queue","nickname":"kim","rated":true}`, TypeQueue, nil},
+		{"tournament check-in", `{"type":"tournament_checkin","tournamentId":1,"nickname":"kim","checkInToken":"t"}`, TypeTournamentCheckIn, nil},
+		{"click", `{"type":"click","ballId":3,"x":10.5,"y":20,"elapsed":0.31}`, TypeClick, nil},
+		{"click without coordinates", `{"type":"click","ballId":3}`, TypeClick, nil},
+		{"chat", `{"type":"chat","text":"안녕"}`, TypeChat, nil},
+		{"emote", `{"type":"emote","emote":"gg"}`, TypeEmote, nil},
+		{"start", `{"type":"start"}`, TypeStart, nil},
+		{"leave", `{"type":"leave"}`, TypeLeave, nil},
+
+		{"broken json", `{"type":`, "", ErrMalformedMessage},
+		{"not an object", `[1,2]`, "", ErrMalformedMessage},
+		{"missing type", `{"nickname":"kim"}`, "", ErrMalformedMessage},
+		{"unknown type", `{"type":"teleport"}`, "", ErrUnknownType},
+		{"unknown field", `{"type":"join","nickname":"kim","roomCode":"ABC123","admin":true}`, "", ErrInvalidMessage},
+		{"wrong field type", `{"type":"click","ballId":"3","x":1,"y":1}`, "", ErrInvalidMessage},
+		{"extra field on empty message", `{"type":"start","now":true}`, "", ErrInvalidMessage},
+		{"unsupported version", `{"type":"hello","version":99}`, "", ErrUnsupportedVersion},
+		{"version below minimum", `{"type":"hello","version":0}`, "", ErrUnsupportedVersion},
+		{"create without nickname", `{"type":"create"}`, "", ErrNicknameRequired},
+		{"join without room code", `{"type":"join","nickname":"kim"}`, "", ErrRoomCodeRequired},
+		{"join without nickname", `{"type":"join","roomCode":"ABC123"}`, "", ErrNicknameRequired},
+		{"rejoin without room code", `{"type":"rejoin","reconnectToken":"t"}`, "", ErrRoomCodeRequired},
+		{"blank chat", `{"type":"chat","text":"   "}`, "", ErrChatEmpty},
+		{"chat too long", `{"type":"chat","text":"` + strings.Repeat("가", MaxChatLength+1) + `"}`, "", ErrChatTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := Decode([]byte(tt.data))
+			if err != tt.wantErr {
+				t.Fatalf("Decode() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if msg != nil {
+					t.Errorf("Decode() returned %T with error", msg)
+				}
+				return
+			}
+			if got := msg.MessageType(); got != tt.wantType {
+				t.Errorf("MessageType() = %q, want %q", got, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestDecodeFields(t *testing.T) {
+	msg, err := Decode([]byte(`{"type":"click","ballId":7,"x":12.5,"y":40,"elapsed":0.25}`))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	click, ok := msg.(*Click)
+	if !ok {
+		t.Fatalf("Decode() = %T, want *Click", msg)
+	}
+	if click.BallID != 7 || click.X == nil || *click.X != 12.5 || click.Y == nil || *click.Y != 40 {
+		t.Errorf("click = %+v", click)
+	}
+	if click.Elapsed == nil || *click.Elapsed != 0.25 {
+		t.Errorf("click.Elapsed = %v, want 0.25", click.Elapsed)
+	}
+}
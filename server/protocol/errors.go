@@ -0,0 +1,114 @@
+package protocol
+
+import "errors"
+
+// ErrorCode identifies an error sent to the client; Message is only for display
+type ErrorCode string
+
+// Protocol errors
+const (
+	CodeMalformedMessage   ErrorCode = "malformed_message"
+	CodeUnknownType        ErrorCode = "unknown_type"
+	CodeUnsupportedFrame   ErrorCode = "unsupported_frame"
+	CodeUnsupportedVersion ErrorCode = "unsupported_version"
+	CodeInvalidMessage     ErrorCode = "invalid_message"
+	CodeInternal           ErrorCode = "internal_error"
+)
+
+// Room and matchmaking errors
+const (
+	CodeNicknameRequired ErrorCode = "nickname_required"
+	CodeInvalidNickname  ErrorCode = "invalid_nickname"
+	CodeRoomCodeRequired ErrorCode = "room_code_required"
+	CodeAlreadyInRoom    ErrorCode = "already_in_room"
+	CodeBusy             ErrorCode = "busy"
+	CodeInvalidCapacity  ErrorCode = "invalid_capacity"
+	CodeRoomNotFound     ErrorCode = "room_not_found"
+	CodeRoomFull         ErrorCode = "room_full"
+	CodeRoomNotAvailable ErrorCode = "room_not_available"
+	CodeRoomExpired      ErrorCode = "room_expired"
+	CodeInvalidReconnect ErrorCode = "invalid_reconnect"
+	CodeSpectatorsFull   ErrorCode = "spectators_full"
+	CodeAlreadyQueued    ErrorCode = "already_queued"
+	CodeNotHost          ErrorCode = "not_host"
+	CodeNotEnoughPlayers ErrorCode = "not_enough_players"
+)
+
+// Rule errors
+const (
+	CodeInvalidRules      ErrorCode = "invalid_rules"
+	CodeInvalidDuration   ErrorCode = "invalid_duration"
+	CodeInvalidDifficulty ErrorCode = "invalid_difficulty"
+	CodeInvalidCurve      ErrorCode = "invalid_curve"
+	CodeInvalidPenalty    ErrorCode = "invalid_penalty"
+	CodeInvalidBalls      ErrorCode = "invalid_balls"
+	CodeInvalidBestOf     ErrorCode = "invalid_best_of"
+)
+
+// Click errors
+const (
+	CodeInvalidClick ErrorCode = "invalid_click"
+	CodeClickMissed  ErrorCode = "click_missed"
+	CodeClickTooFast ErrorCode = "click_too_fast"
+	CodeClickBlocked ErrorCode = "click_blocked"
+)
+
+// Chat errors
+const (
+	CodeChatEmpty       ErrorCode = "chat_empty"
+	CodeChatTooLong     ErrorCode = "chat_too_long"
+	CodeChatInvalid     ErrorCode = "chat_invalid"
+	CodeChatRateLimited ErrorCode = "chat_rate_limited"
+	CodeChatBlocked     ErrorCode = "chat_blocked"
+	CodeUnknownEmote    ErrorCode = "unknown_emote"
+)
+
+// Tournament errors
+const (
+	CodeTournamentNotFound ErrorCode = "tournament_not_found"
+	CodeTournamentFinished ErrorCode = "tournament_finished"
+	CodeInvalidTournament  ErrorCode = "invalid_tournament"
+	CodeNotEntrant         ErrorCode = "not_entrant"
+	CodeInvalidCheckIn     ErrorCode = "invalid_checkin"
+	CodeEliminated         ErrorCode = "eliminated"
+	CodeMatchInProgress    ErrorCode = "match_in_progress"
+)
+
+// Error is a protocol-level error reported to the client
+type Error struct {
+	Code    ErrorCode
+	Message string
+}
+
+func (e Error) Error() string {
+	return e.Message
+}
+
+func (e Error) ErrorCode() ErrorCode {
+	return e.Code
+}
+
+var (
+	ErrMalformedMessage   = Error{CodeMalformedMessage, "잘못된 메시지 형식입니다"}
+	ErrUnknownType        = Error{CodeUnknownType, "알 수 없는 메시지 타입입니다"}
+	ErrUnsupportedFrame   = Error{CodeUnsupportedFrame, "바이너리 메시지는 지원하지 않습니다"}
+	ErrUnsupportedVersion = Error{CodeUnsupportedVersion, "지원하지 않는 프로토콜 버전입니다"}
+	ErrInvalidMessage     = Error{CodeInvalidMessage, "잘못된 메시지입니다"}
+	ErrNicknameRequired   = Error{CodeNicknameRequired, "닉네임을 입력해주세요"}
+	ErrInvalidNickname    = Error{CodeInvalidNickname, "닉네임은 20자 이하의 한글, 영문, 숫자만 사용할 수 있습니다"}
+	ErrRoomCodeRequired   = Error{CodeRoomCodeRequired, "방 코드를 입력해주세요"}
+	ErrAlreadyInRoom      = Error{CodeAlreadyInRoom, "이미 방에 참가 중입니다"}
+	ErrBusy               = Error{CodeBusy, "관전 또는 매칭 대기 중에는 참가할 수 없습니다"}
+	ErrChatEmpty          = Error{CodeChatEmpty, "메시지를 입력해주세요"}
+	ErrChatTooLong        = Error{CodeChatTooLong, "메시지는 100자 이하여야 합니다"}
+	ErrChatInvalid        = Error{CodeChatInvalid, "사용할 수 없는 문자가 포함되어 있습니다"}
+)
+
+// CodeOf returns the code carried by err, or CodeInternal if it has none
+func CodeOf(err error) ErrorCode {
+	var coded interface{ ErrorCode() ErrorCode }
+	if errors.As(err, &coded) {
+		return coded.ErrorCode()
+	}
+	return CodeInternal
+}
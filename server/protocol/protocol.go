@@ -0,0 +1,69 @@
+// Package protocol defines the battle WebSocket protocol: message types,
+// the hello handshake, client messages and their validation, error codes
+// and the close codes used when a connection breaks the protocol.
+package protocol
+
+import "github.com/gorilla/websocket"
+
+// 프로토콜 버전
+const (
+	Version    = 1
+	MinVersion = 1
+)
+
+// 클라이언트 프레임 최대 크기 (바이트)
+const MaxFrameSize = 4096
+
+// 채팅 메시지 최대 길이 (글자 수)
+const MaxChatLength = 100
+
+// Capabilities advertised in the server hello
+const (
+	CapMultiBall   = "multi_ball"
+	CapSpectate    = "spectate"
+	CapMatchmaking = "matchmaking"
+	CapBots        = "bots"
+	CapReplays     = "replays"
+	CapChat        = "chat"
+	CapTournaments = "tournaments"
+	CapLobby       = "lobby"
+)
+
+// Capabilities lists every capability this server supports
+var Capabilities = []string{
+	CapMultiBall,
+	CapSpectate,
+	CapMatchmaking,
+	CapBots,
+	CapReplays,
+	CapChat,
+	CapTournaments,
+	CapLobby,
+}
+
+// HelloMsg answers the client hello with the negotiated version
+type HelloMsg struct {
+	Type         MessageType `json:"type"`
+	Version      int         `json:"version"`
+	Capabilities []string    `json:"capabilities"`
+	Encoding     string      `json:"encoding"` // 서브프로토콜로 정해진 인코딩
+}
+
+// NewHello builds the server hello for a connection's subprotocol
+func NewHello(subprotocol string) HelloMsg {
+	return HelloMsg{Type: TypeHello, Version: Version, Capabilities: Capabilities, Encoding: Encoding(subprotocol)}
+}
+
+// 연결을 끊는 오류의 WebSocket 종료 코드
+var closeCodes = map[ErrorCode]int{
+	CodeMalformedMessage:   websocket.CloseInvalidFramePayloadData,
+	CodeUnknownType:        websocket.CloseUnsupportedData,
+	CodeUnsupportedFrame:   websocket.CloseUnsupportedData,
+	CodeUnsupportedVersion: websocket.CloseProtocolError,
+}
+
+// CloseCode returns the close code for errors that end the connection,
+// or 0 if the client may keep sending
+func CloseCode(err error) int {
+	return closeCodes[CodeOf(err)]
+}
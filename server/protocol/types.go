@@ -0,0 +1,61 @@
+package protocol
+
+// MessageType is the "type" field of every battle message
+type MessageType string
+
+// Client to server messages
+const (
+	TypeHello             MessageType = "hello"
+	TypeCreate            MessageType = "create"
+	TypePlayBot           MessageType = "play_bot"
+	TypeJoin              MessageType = "join"
+	TypeStart             MessageType = "start"
+	TypeRejoin            MessageType = "rejoin"
+	TypeSpectate          MessageType = "spectate"
+	TypeQueue             MessageType = "queue"
+	TypeTournamentCheckIn MessageType = "tournament_checkin"
+	TypeLobbySubscribe    MessageType = "lobby_subscribe"
+	TypeLobbyUnsubscribe  MessageType = "lobby_unsubscribe"
+	TypeCancelQueue       MessageType = "cancel_queue"
+	TypeClick             MessageType = "click"
+	TypeChat              MessageType = "chat"
+	TypeEmote             MessageType = "emote"
+	TypeReadyRematch      MessageType = "ready_rematch"
+	TypeLeave             MessageType = "leave"
+)
+
+// Server to client messages (chat and emote share the client types)
+const (
+	TypeRoomCreated          MessageType = "room_created"
+	TypeRoomJoined           MessageType = "room_joined"
+	TypeOpponentJoined       MessageType = "opponent_joined"
+	TypePlayerJoined         MessageType = "player_joined"
+	TypePlayerLeft           MessageType = "player_left"
+	TypeOpponentLeft         MessageType = "opponent_left"
+	TypeOpponentDisconnected MessageType = "opponent_disconnected"
+	TypeOpponentReconnected  MessageType = "opponent_reconnected"
+	TypeHostChanged          MessageType = "host_changed"
+	TypeRoomClosed           MessageType = "room_closed"
+	TypeRejoined             MessageType = "rejoined"
+	TypeSpectating           MessageType = "spectating"
+	TypeSpectatorCount       MessageType = "spectator_count"
+	TypeCountdown            MessageType = "countdown"
+	TypeGameStart            MessageType = "game_start"
+	TypeTimeUpdate           MessageType = "time_update"
+	TypeBallSpawn            MessageType = "ball_spawn"
+	TypeBallResult           MessageType = "ball_result"
+	TypeRoundEnd             MessageType = "round_end"
+	TypeGameEnd              MessageType = "game_end"
+	TypeSeriesEnd            MessageType = "series_end"
+	TypeOpponentReady        MessageType = "opponent_ready"
+	TypeRematchStart         MessageType = "rematch_start"
+	TypeMatchFound           MessageType = "match_found"
+	TypeQueueStatus          MessageType = "queue_status"
+	TypeQueueCancelled       MessageType = "queue_cancelled"
+	TypeTournamentCheckedIn  MessageType = "tournament_checked_in"
+	TypeTournamentMatch      MessageType = "tournament_match"
+	TypeLobbyRooms           MessageType = "lobby_rooms"
+	TypeLobbyRoom            MessageType = "lobby_room"
+	TypeLobbyRoomRemoved     MessageType = "lobby_room_removed"
+	TypeError                MessageType = "error"
+)
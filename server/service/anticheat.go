@@ -0,0 +1,88 @@
+package service
+
+import (
+	"log"
+	"math"
+	"time"
+
+	"mini-games/model"
+	"mini-games/protocol"
+)
+
+// 클릭 검증 기준
+const (
+	// 공이 나타난 뒤 이보다 빠른 클릭은 사람이 할 수 없는 반응으로 간주
+	MinReactionTime = 100 * time.Millisecond
+
+	// 공 반경 밖으로 허용하는 오차 (게임 좌표)
+	ClickTolerance = 8.0
+
+	// 누적 부정 점수가 이 값 이상이면 이후 클릭을 무시
+	CheatScoreLimit = 10
+)
+
+var (
+	ErrInvalidClick = RoomError{protocol.CodeInvalidClick, "잘못된 클릭입니다"}
+	ErrClickMissed  = RoomError{protocol.CodeClickMissed, "공을 벗어난 클릭입니다"}
+	ErrClickTooFast = RoomError{protocol.CodeClickTooFast, "비정상적으로 빠른 클릭입니다"}
+	ErrClickBlocked = RoomError{protocol.CodeClickBlocked, "부정 클릭이 감지되어 클릭이 무시됩니다"}
+)
+
+// 리플레이에 기록하는 클릭 거부 사유
+var clickRejectCodes = map[error]string{
+	ErrInvalidClick: "invalid",
+	ErrClickMissed:  "missed",
+	ErrClickTooFast: "fast",
+	ErrClickBlocked: "blocked",
+}
+
+// Click is a click on a ball in game coordinates
+type Click struct {
+	BallID  int
+	X, Y    *float64
+	Elapsed *float64 // 클라이언트가 ball_spawn을 받은 뒤 경과 시간 (초)
+}
+
+// validateClick checks that a click lands on the ball and came after a
+// humanly possible reaction time, measured by the server (see compensatedReaction)
+func validateClick(ball *model.Ball, click Click, serverReaction time.Duration) error {
+	if click.X == nil || click.Y == nil {
+		return ErrInvalidClick
+	}
+	radius := float64(ball.Size)/2 + ClickTolerance
+	if math.Hypot(*click.X-ball.X, *click.Y-ball.Y) > radius {
+		return ErrClickMissed
+	}
+	if serverReaction < MinReactionTime {
+		return ErrClickTooFast
+	}
+	return nil
+}
+
+// flagClick adds a rejected click to the player's cheat score
+func flagClick(room *model.Room, player *model.Player, reason error) {
+	player.CheatScore++
+	if player.CheatScore == CheatScoreLimit {
+		log.Printf("Player %s in room %s reached cheat score %d (last: %v), ignoring clicks",
+			player.Nickname, room.Code, player.CheatScore, reason)
+	}
+}
+
+// recordClick adds a click and its verdict to the room's replay. reaction is
+// -1 when the click never reached validation. Caller must hold room.Mu.
+func recordClick(room *model.Room, player *model.Player, playerIndex int, click Click, reaction time.Duration, err error) {
+	elapsed := int64(-1)
+	if click.Elapsed != nil {
+		elapsed = model.ReplayMillis(*click.Elapsed)
+	}
+	reactionMs := int64(-1)
+	if reaction >= 0 {
+		reactionMs = reaction.Milliseconds()
+	}
+	verdict := ""
+	if err != nil {
+		verdict = clickRejectCodes[err]
+	}
+	room.Replay.Record(model.BattleEventClick, playerIndex, click.BallID, reactionMs, elapsed,
+		player.RTT().Milliseconds(), verdict)
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"database/sql"
+	"log"
+	"math"
+
+	"mini-games/database"
+	"mini-games/model"
+)
+
+// Elo 변동 계수
+const BATTLE_RATING_K = 32.0
+
+// expectedScore is the Elo win expectancy of a rating against another
+func expectedScore(rating, opponent float64) float64 {
+	return 1 / (1 + math.Pow(10, (opponent-rating)/400))
+}
+
+// getRatingTx returns a player's rating, or DefaultRating if unrated
+func getRatingTx(tx *sql.Tx, nickname string) (float64, error) {
+	var rating float64
+	err := tx.QueryRow("SELECT rating FROM battle_ratings WHERE nickname = ?", nickname).Scan(&rating)
+	if err == sql.ErrNoRows {
+		return DefaultRating, nil
+	}
+	return rating, err
+}
+
+// GetBattleRating returns a player's rating, or DefaultRating if unrated
+func GetBattleRating(nickname string) float64 {
+	var rating float64
+	err := database.DB.QueryRow("SELECT rating FROM battle_ratings WHERE nickname = ?", nickname).Scan(&rating)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Failed to load rating for %s: %v", nickname, err)
+		}
+		return DefaultRating
+	}
+	return rating
+}
+
+// RecordBattleMatch stores a finished battle and updates both players'
+// ratings in one transaction. Winner and ratings are filled in on match.
+func RecordBattleMatch(match *model.BattleMatch) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	match.Winner = -1
+	if match.Scores[0] > match.Scores[1] {
+		match.Winner = 0
+	} else if match.Scores[1] > match.Scores[0] {
+		match.Winner = 1
+	}
+
+	for i, nickname := range match.Nicknames {
+		if match.RatingsBefore[i], err = getRatingTx(tx, nickname); err != nil {
+			return err
+		}
+	}
+	match.RatingsAfter = match.RatingsBefore
+
+	// 같은 닉네임끼리의 대전은 기록만 하고 레이팅은 반영하지 않음
+	if match.Nicknames[0] != match.Nicknames[1] {
+		for i := range match.Nicknames {
+			actual := 0.5
+			if match.Winner == i {
+				actual = 1
+			} else if match.Winner == 1-i {
+				actual = 0
+			}
+			expected := expectedScore(match.RatingsBefore[i], match.RatingsBefore[1-i])
+			match.RatingsAfter[i] = match.RatingsBefore[i] + BATTLE_RATING_K*(actual-expected)
+		}
+
+		for i, nickname := range match.Nicknames {
+			win, loss, draw := 0, 0, 0
+			switch match.Winner {
+			case i:
+				win = 1
+			case 1 - i:
+				loss = 1
+			default:
+				draw = 1
+			}
+			if _, err := tx.Exec(
+				`INSERT INTO battle_ratings (nickname, rating, games, wins, losses, draws)
+				 VALUES (?, ?, 1, ?, ?, ?)
+				 ON CONFLICT(nickname) DO UPDATE SET
+				   rating = excluded.rating,
+				   games = games + 1,
+				   wins = wins + excluded.wins,
+				   losses = losses + excluded.losses,
+				   draws = draws + excluded.draws,
+				   updated_at = CURRENT_TIMESTAMP`,
+				nickname, match.RatingsAfter[i], win, loss, draw,
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	result, err := tx.Exec(
+		`INSERT INTO battle_matches
+		 (room_code, nickname1, nickname2, score1, score2, winner, rounds, duration, ball_count,
+		  rating1_before, rating2_before, rating1_after, rating2_after)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		match.RoomCode, match.Nicknames[0], match.Nicknames[1], match.Scores[0], match.Scores[1],
+		match.Winner, match.Rounds, match.Duration, match.BallCount,
+		match.RatingsBefore[0], match.RatingsBefore[1], match.RatingsAfter[0], match.RatingsAfter[1],
+	)
+	if err != nil {
+		return err
+	}
+	if match.ID, err = result.LastInsertId(); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func GetBattleLeaderboard(limit int) ([]model.BattleRating, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	rows, err := database.DB.Query(
+		`SELECT nickname, rating, games, wins, losses, draws, updated_at
+		 FROM battle_ratings
+		 ORDER BY rating DESC
+		 LIMIT ?`,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ratings []model.BattleRating
+	for rows.Next() {
+		var r model.BattleRating
+		err := rows.Scan(&r.Nickname, &r.Rating, &r.Games, &r.Wins, &r.Losses, &r.Draws, &r.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, r)
+	}
+
+	return ratings, rows.Err()
+}
+
+func GetBattleHistory(nickname string, limit int) ([]model.BattleMatch, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	rows, err := database.DB.Query(
+		`SELECT id, room_code, nickname1, nickname2, score1, score2, winner, rounds, duration, ball_count,
+		        rating1_before, rating2_before, rating1_after, rating2_after, created_at
+		 FROM battle_matches
+		 WHERE nickname1 = ? OR nickname2 = ?
+		 ORDER BY id DESC
+		 LIMIT ?`,
+		nickname, nickname, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []model.BattleMatch
+	for rows.Next() {
+		var m model.BattleMatch
+		err := rows.Scan(
+			&m.ID, &m.RoomCode, &m.Nicknames[0], &m.Nicknames[1], &m.Scores[0], &m.Scores[1],
+			&m.Winner, &m.Rounds, &m.Duration, &m.BallCount,
+			&m.RatingsBefore[0], &m.RatingsBefore[1], &m.RatingsAfter[0], &m.RatingsAfter[1], &m.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+
+	return matches, rows.Err()
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"mini-games/model"
+)
+
+// BotProfile controls how a server-side opponent plays
+type BotProfile struct {
+	ReactionMean   time.Duration
+	ReactionStdDev time.Duration
+	BlueMistake    float64 // 파란 공을 빨간 공으로 착각해 누를 확률
+	MissRate       float64 // 빨간 공을 놓칠 확률
+	Aim            float64 // 중심에서 벗어나는 최대 거리 (공 반지름 대비)
+}
+
+// 난이도별 봇 성향
+var botProfiles = map[string]BotProfile{
+	model.DifficultyEasy: {
+		ReactionMean:   650 * time.Millisecond,
+		ReactionStdDev: 150 * time.Millisecond,
+		BlueMistake:    0.35,
+		MissRate:       0.30,
+		Aim:            0.9,
+	},
+	model.DifficultyNormal: {
+		ReactionMean:   450 * time.Millisecond,
+		ReactionStdDev: 100 * time.Millisecond,
+		BlueMistake:    0.15,
+		MissRate:       0.15,
+		Aim:            0.7,
+	},
+	model.DifficultyHard: {
+		ReactionMean:   300 * time.Millisecond,
+		ReactionStdDev: 60 * time.Millisecond,
+		BlueMistake:    0.05,
+		MissRate:       0.05,
+		Aim:            0.4,
+	},
+}
+
+var botNicknames = map[string]string{
+	model.DifficultyEasy:   "AI 봇 (쉬움)",
+	model.DifficultyNormal: "AI 봇 (보통)",
+	model.DifficultyHard:   "AI 봇 (어려움)",
+}
+
+const (
+	// 봇의 최소 반응 시간 (MinReactionTime보다 여유 있게)
+	BotMinReaction = 150 * time.Millisecond
+
+	// 게임 종료 후 봇이 재대결 준비를 누르기까지의 시간
+	BotRematchDelay = 1 * time.Second
+)
+
+// bot drives a server-controlled player from the messages the room sends it
+type bot struct {
+	rm      *RoomManager
+	room    *model.Room
+	player  *model.Player
+	profile BotProfile
+}
+
+// AddBot seats a server-controlled opponent with the given difficulty in a room
+func (rm *RoomManager) AddBot(room *model.Room, difficulty string) (*model.Player, error) {
+	profile, ok := botProfiles[difficulty]
+	if !ok {
+		return nil, ErrInvalidDifficulty
+	}
+
+	b := &bot{rm: rm, room: room, profile: profile}
+	b.player = &model.Player{
+		Nickname:   botNicknames[difficulty],
+		Bot:        true,
+		Deliver:    b.receive,
+		LastActive: time.Now(),
+	}
+	if _, err := rm.JoinRoom(room.Code, b.player); err != nil {
+		return nil, err
+	}
+	return b.player, nil
+}
+
+// receive handles a message sent to the bot. It is called with room.Mu held,
+// so every action is scheduled rather than taken directly.
+func (b *bot) receive(v interface{}) {
+	switch msg := v.(type) {
+	case model.BallSpawnMsg:
+		b.react(msg)
+	case model.GameEndMsg, model.SeriesEndMsg:
+		time.AfterFunc(BotRematchDelay, func() {
+			b.rm.HandleRematchReady(b.room, b.player.Index)
+		})
+	}
+}
+
+// react decides whether and when to click a newly spawned ball
+func (b *bot) react(ball model.BallSpawnMsg) {
+	p := b.profile
+	if ball.IsRed && rand.Float64() < p.MissRate {
+		return
+	}
+	if !ball.IsRed && rand.Float64() >= p.BlueMistake {
+		return
+	}
+
+	reaction := p.ReactionMean + time.Duration(rand.NormFloat64()*float64(p.ReactionStdDev))
+	if reaction < BotMinReaction {
+		reaction = BotMinReaction
+	}
+	// 시간 안에 누를 수 없으면 포기
+	if reaction.Seconds() >= ball.TimeLimit {
+		return
+	}
+
+	angle := rand.Float64() * 2 * math.Pi
+	distance := rand.Float64() * p.Aim * float64(ball.Size) / 2
+	x := ball.X + math.Cos(angle)*distance
+	y := ball.Y + math.Sin(angle)*distance
+	elapsed := reaction.Seconds()
+	click := Click{BallID: ball.ID, X: &x, Y: &y, Elapsed: &elapsed}
+
+	time.AfterFunc(reaction, func() {
+		b.rm.HandleClick(b.room, b.player.Index, click)
+	})
+}
+
+// hasBot reports whether a server-controlled player is seated in the room.
+// Caller must hold room.Mu.
+func hasBot(room *model.Room) bool {
+	for _, p := range room.Players {
+		if p != nil && p.Bot {
+			return true
+		}
+	}
+	return false
+}
+
+// humanCount returns the number of seated human players. Caller must hold
+// room.Mu.
+func humanCount(room *model.Room) int {
+	count := 0
+	for _, p := range room.Players {
+		if p != nil && !p.Bot {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"mini-games/model"
+	"mini-games/protocol"
+)
+
+const (
+	// 플레이어당 ChatRateWindow 동안 최대 ChatRateLimit개 (채팅 + 이모트)
+	ChatRateWindow = 5 * time.Second
+	ChatRateLimit  = 5
+)
+
+var (
+	ErrChatRateLimited = RoomError{protocol.CodeChatRateLimited, "메시지를 너무 자주 보내고 있습니다"}
+	ErrChatBlocked     = RoomError{protocol.CodeChatBlocked, "보낼 수 없는 메시지입니다"}
+	ErrUnknownEmote    = RoomError{protocol.CodeUnknownEmote, "알 수 없는 이모트입니다"}
+)
+
+// 빠른 이모트 목록
+var Emotes = map[string]bool{
+	"hi":     true,
+	"gg":     true,
+	"wow":    true,
+	"oops":   true,
+	"thanks": true,
+	"angry":  true,
+}
+
+// ChatFilter inspects chat text before it is broadcast. It returns the text
+// to send (e.g. with words masked) or an error to reject the message.
+type ChatFilter func(text string) (string, error)
+
+// NewWordFilter returns a ChatFilter that masks the given words
+// (case-insensitive) with asterisks
+func NewWordFilter(words []string) ChatFilter {
+	var banned []string
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			banned = append(banned, word)
+		}
+	}
+
+	return func(text string) (string, error) {
+		runes := []rune(text)
+		lower := make([]rune, len(runes))
+		for i, r := range runes {
+			lower[i] = unicode.ToLower(r)
+		}
+		for _, word := range banned {
+			target := []rune(word)
+			for i := 0; i+len(target) <= len(lower); i++ {
+				if string(lower[i:i+len(target)]) == word {
+					for j := i; j < i+len(target); j++ {
+						runes[j] = '*'
+					}
+				}
+			}
+		}
+		return string(runes), nil
+	}
+}
+
+// allowChat applies the per-player chat rate limit. Caller must hold room.Mu.
+func allowChat(player *model.Player) bool {
+	now := time.Now()
+	if now.Sub(player.ChatWindowStart) > ChatRateWindow {
+		player.ChatWindowStart = now
+		player.ChatCount = 0
+	}
+	if player.ChatCount >= ChatRateLimit {
+		return false
+	}
+	player.ChatCount++
+	return true
+}
+
+// SendChat broadcasts a chat message from a player to the room
+func (rm *RoomManager) SendChat(room *model.Room, playerIndex int, text string) error {
+	if rm.ChatFilter != nil {
+		filtered, err := rm.ChatFilter(text)
+		if err != nil {
+			return ErrChatBlocked
+		}
+		text = filtered
+	}
+
+	room.Mu.Lock()
+	defer room.Mu.Unlock()
+
+	if playerIndex < 0 || playerIndex >= len(room.Players) || room.Players[playerIndex] == nil {
+		return nil
+	}
+	player := room.Players[playerIndex]
+	if !allowChat(player) {
+		return ErrChatRateLimited
+	}
+
+	broadcast(room, model.ChatMsg{
+		Type:        protocol.TypeChat,
+		PlayerIndex: playerIndex,
+		Nickname:    player.Nickname,
+		Text:        text,
+	})
+	return nil
+}
+
+// SendEmote broadcasts a quick emote from a player to the room
+func (rm *RoomManager) SendEmote(room *model.Room, playerIndex int, emote string) error {
+	if !Emotes[emote] {
+		return ErrUnknownEmote
+	}
+
+	room.Mu.Lock()
+	defer room.Mu.Unlock()
+
+	if playerIndex < 0 || playerIndex >= len(room.Players) || room.Players[playerIndex] == nil {
+		return nil
+	}
+	player := room.Players[playerIndex]
+	if !allowChat(player) {
+		return ErrChatRateLimited
+	}
+
+	broadcast(room, model.EmoteMsg{
+		Type:        protocol.TypeEmote,
+		PlayerIndex: playerIndex,
+		Nickname:    player.Nickname,
+		Emote:       emote,
+	})
+	return nil
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"mini-games/model"
+)
+
+// Game is a server-validated game plugged into the session engine.
+// All methods are called while the session store lock is held.
+type Game interface {
+	// Start initializes a new session and returns the start response
+	Start(session *model.GameSession, params json.RawMessage) (interface{}, error)
+	// Apply handles an in-game event such as "click" or "flip"
+	Apply(session *model.GameSession, event string, payload json.RawMessage) (interface{}, error)
+	// Finish ends the session, verifying any submitted log
+	Finish(session *model.GameSession, payload json.RawMessage) (interface{}, error)
+	// Score returns the verified score of an ended session
+	Score(session *model.GameSession) int
+}
+
+// ReplayRecorder is implemented by games that store an event log with the score
+type ReplayRecorder interface {
+	SaveReplay(scoreID int64, session *model.GameSession) error
+}
+
+// StatefulGame is implemented by games that keep data in session.State,
+// so persistent session stores can restore its concrete type
+type StatefulGame interface {
+	NewState() interface{}
+}
+
+// StatelessGame is implemented by games whose sessions travel in signed
+// tokens instead of the session store
+type StatelessGame interface {
+	// EncodeState returns the compact state carried in the token
+	EncodeState(session *model.GameSession) (json.RawMessage, error)
+	// DecodeState restores session.State from a token
+	DecodeState(session *model.GameSession, state json.RawMessage) error
+}
+
+// SubmitVerifier is implemented by games that need the submit request
+// (e.g. a full event log) to verify a session before its score is saved
+type SubmitVerifier interface {
+	VerifySubmit(session *model.GameSession, payload json.RawMessage) error
+}
+
+var (
+	ErrUnknownGame     = errors.New("Unknown game")
+	ErrUnknownEvent    = errors.New("Unknown action")
+	ErrSessionNotFound = errors.New("Session not found")
+	ErrInvalidParams   = errors.New("Invalid request body")
+	ErrScoreNotSaved   = errors.New("Failed to save score")
+)
+
+// 게임 레지스트리 (키는 allowedGames와 동일)
+var games = make(map[string]Game)
+
+// RegisterGame adds a game to the registry
+func RegisterGame(id string, game Game) {
+	games[id] = game
+}
+
+// GetGame returns a registered game by ID
+func GetGame(id string) (Game, bool) {
+	game, ok := games[id]
+	return game, ok
+}
+
+// decodeParams decodes a request payload into v
+func decodeParams(payload json.RawMessage, v interface{}) error {
+	if len(payload) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return ErrInvalidParams
+	}
+	return nil
+}
+
+// encodeSession issues a new token for stateless games
+func encodeSession(game Game, session *model.GameSession) (string, error) {
+	stateless, ok := game.(StatelessGame)
+	if !ok {
+		return "", nil
+	}
+	state, err := stateless.EncodeState(session)
+	if err != nil {
+		return "", err
+	}
+	return EncodeSessionToken(session, state)
+}
+
+// withSession runs fn on a session loaded from the store or, for stateless
+// games, from the token. It returns the refreshed token for stateless games.
+func withSession(gameID string, sessionID string, token string, fn func(game Game, session *model.GameSession) error) (string, error) {
+	game, ok := GetGame(gameID)
+	if !ok {
+		return "", ErrUnknownGame
+	}
+
+	stateless, ok := game.(StatelessGame)
+	if !ok {
+		return "", sessionStore.Update(sessionID, func(session *model.GameSession) error {
+			if session.Game != gameID {
+				return ErrSessionNotFound
+			}
+			return fn(game, session)
+		})
+	}
+
+	session, state, err := DecodeSessionToken(token)
+	if err != nil {
+		return "", err
+	}
+	if session.Game != gameID || session.ID != sessionID {
+		return "", ErrInvalidToken
+	}
+	if err := stateless.DecodeState(session, state); err != nil {
+		return "", ErrInvalidToken
+	}
+
+	if err := fn(game, session); err != nil {
+		return "", err
+	}
+	return encodeSession(game, session)
+}
+
+// StartSession creates a new session for the given game.
+// The returned token is empty unless the game is stateless.
+func StartSession(gameID string, params json.RawMessage) (interface{}, string, error) {
+	game, ok := GetGame(gameID)
+	if !ok {
+		return nil, "", ErrUnknownGame
+	}
+
+	session := &model.GameSession{
+		ID:        generateSessionID(),
+		Game:      gameID,
+		Seed:      generateSeed(),
+		StartTime: time.Now(),
+		Status:    "playing",
+	}
+
+	response, err := game.Start(session, params)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if _, ok := game.(StatelessGame); ok {
+		token, err := encodeSession(game, session)
+		return response, token, err
+	}
+
+	if err := sessionStore.Put(session); err != nil {
+		return nil, "", err
+	}
+	return response, "", nil
+}
+
+// logEvent records an applied event in the session store (stateful games only)
+func logEvent(gameID string, sessionID string, event string, payload json.RawMessage) {
+	game, ok := GetGame(gameID)
+	if !ok {
+		return
+	}
+	if _, stateless := game.(StatelessGame); stateless {
+		return
+	}
+	if err := sessionStore.AppendEvent(sessionID, event, payload); err != nil {
+		log.Printf("Failed to record %s event for session %s: %v", event, sessionID, err)
+	}
+}
+
+// ApplyEvent forwards an in-game event to the session's game
+func ApplyEvent(gameID string, sessionID string, token string, event string, payload json.RawMessage) (interface{}, string, error) {
+	var response interface{}
+	newToken, err := withSession(gameID, sessionID, token, func(game Game, session *model.GameSession) error {
+		var err error
+		response, err = game.Apply(session, event, payload)
+		return err
+	})
+	if err == nil {
+		logEvent(gameID, sessionID, event, payload)
+	}
+	return response, newToken, err
+}
+
+// FinishSession ends the session through its game
+func FinishSession(gameID string, sessionID string, token string, payload json.RawMessage) (interface{}, string, error) {
+	var response interface{}
+	newToken, err := withSession(gameID, sessionID, token, func(game Game, session *model.GameSession) error {
+		var err error
+		response, err = game.Finish(session, payload)
+		return err
+	})
+	if err == nil {
+		logEvent(gameID, sessionID, "finish", payload)
+	}
+	return response, newToken, err
+}
+
+// SubmitSessionScore saves the verified score of an ended session
+func SubmitSessionScore(gameID string, sessionID string, token string, nickname string, payload json.RawMessage) (model.SubmitScoreResponse, error) {
+	var score int
+	var submitted *model.GameSession
+	var submittedGame Game
+	_, err := withSession(gameID, sessionID, token, func(game Game, session *model.GameSession) error {
+		if session.Status != "ended" {
+			return nil
+		}
+		if verifier, ok := game.(SubmitVerifier); ok {
+			if err := verifier.VerifySubmit(session, payload); err != nil {
+				return err
+			}
+		}
+		// 토큰 세션은 DB에 제출 기록 (중복 제출 방지)
+		if _, ok := game.(StatelessGame); ok {
+			if err := claimSubmission(session); err != nil {
+				return err
+			}
+		}
+		// 상태를 submitted로 변경 (중복 제출 방지)
+		session.Status = "submitted"
+		score = game.Score(session)
+		submitted = session
+		submittedGame = game
+		return nil
+	})
+	if err != nil || submitted == nil {
+		return model.SubmitScoreResponse{Success: false}, err
+	}
+
+	// DB에 점수 저장 (실패하면 제출 표시를 되돌려 다시 제출할 수 있게 함)
+	scoreID, err := SaveScore(model.ScoreInput{
+		Nickname: nickname,
+		Game:     gameID,
+		Score:    score,
+	})
+	if err != nil {
+		log.Printf("Failed to save %s score for session %s: %v", gameID, sessionID, err)
+		releaseSubmission(submittedGame, submitted)
+		return model.SubmitScoreResponse{Success: false}, ErrScoreNotSaved
+	}
+
+	// 재검증용 이벤트 기록 저장
+	if recorder, ok := submittedGame.(ReplayRecorder); ok {
+		if err := recorder.SaveReplay(scoreID, submitted); err != nil {
+			log.Printf("Failed to save replay for score %d: %v", scoreID, err)
+		}
+	}
+
+	return model.SubmitScoreResponse{
+		Success: true,
+		ScoreID: scoreID,
+	}, nil
+}
+
+// releaseSubmission undoes the submitted mark of a session whose score could
+// not be saved
+func releaseSubmission(game Game, session *model.GameSession) {
+	var err error
+	if _, ok := game.(StatelessGame); ok {
+		err = unclaimSubmission(session)
+	} else {
+		err = sessionStore.Update(session.ID, func(stored *model.GameSession) error {
+			if stored.Status == "submitted" {
+				stored.Status = "ended"
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		log.Printf("Failed to release submission of session %s: %v", session.ID, err)
+	}
+}
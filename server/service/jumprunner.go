@@ -0,0 +1,199 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"mini-games/model"
+)
+
+// JumpRunner 물리 상수 (클라이언트와 동일해야 함)
+const (
+	JR_GAME_WIDTH      = 1200
+	JR_GROUND_HEIGHT   = 40
+	JR_PLAYER_X        = 50
+	JR_PLAYER_WIDTH    = 70
+	JR_PLAYER_HEIGHT   = 70
+	JR_OBSTACLE_WIDTH  = 60
+	JR_OBSTACLE_HEIGHT = 75
+	JR_GRAVITY         = 0.8
+	JR_JUMP_FORCE      = 18
+	JR_INITIAL_SPEED   = 6
+	JR_SPEED_INCREMENT = 0.002
+
+	// 고정 프레임 간격 (60fps)
+	JR_FRAME_MS = 1000.0 / 60.0
+
+	// 시뮬레이션 최대 프레임 수 (약 1시간)
+	JR_MAX_FRAMES = 60 * 60 * 60
+
+	// 플레이 시간 허용 오차 (ms)
+	JR_TIME_TOLERANCE = 500
+)
+
+var (
+	ErrInvalidJumpOrder = errors.New("Jumps must be in increasing frame order")
+	ErrCollidedEarlier  = errors.New("Inputs would have collided earlier")
+	ErrFrameMismatch    = errors.New("Reported frame count does not match simulation")
+)
+
+// jumpRunnerCollides checks the player against all obstacles
+func jumpRunnerCollides(playerY float64, obstacles []model.JumpRunnerObstacle) bool {
+	playerLeft := float64(JR_PLAYER_X)
+	playerRight := playerLeft + JR_PLAYER_WIDTH - 10
+	playerBottom := JR_GROUND_HEIGHT + playerY
+	playerTop := playerBottom + JR_PLAYER_HEIGHT
+
+	for _, obs := range obstacles {
+		obsLeft := obs.X + 5
+		obsRight := obs.X + JR_OBSTACLE_WIDTH - 5
+		obsBottom := float64(JR_GROUND_HEIGHT)
+		obsTop := JR_GROUND_HEIGHT + obs.Height
+
+		if playerRight > obsLeft && playerLeft < obsRight && playerBottom < obsTop && playerTop > obsBottom {
+			return true
+		}
+	}
+	return false
+}
+
+// SimulateJumpRunner replays a jump timeline with a fixed timestep until
+// the runner collides with an obstacle
+func SimulateJumpRunner(seed int64, jumps []int) (model.JumpRunnerResult, error) {
+	for i, frame := range jumps {
+		if frame < 0 || (i > 0 && frame <= jumps[i-1]) {
+			return model.JumpRunnerResult{}, ErrInvalidJumpOrder
+		}
+	}
+
+	rng := NewMulberry32(seed)
+	var obstacles []model.JumpRunnerObstacle
+	var playerY, velocity, obstacleTimer, distance float64
+	speed := float64(JR_INITIAL_SPEED)
+	jumping := false
+	nextJump := 0
+
+	for frame := 0; frame < JR_MAX_FRAMES; frame++ {
+		// 점프 입력 (공중에서는 무시)
+		if nextJump < len(jumps) && jumps[nextJump] == frame {
+			if !jumping {
+				velocity = JR_JUMP_FORCE
+				jumping = true
+			}
+			nextJump++
+		}
+
+		// 중력 및 위치 갱신
+		velocity -= JR_GRAVITY
+		playerY += velocity
+		if playerY <= 0 {
+			playerY = 0
+			velocity = 0
+			jumping = false
+		}
+
+		// 장애물 이동
+		remaining := obstacles[:0]
+		for _, obs := range obstacles {
+			obs.X -= speed
+			if obs.X > -JR_OBSTACLE_WIDTH {
+				remaining = append(remaining, obs)
+			}
+		}
+		obstacles = remaining
+
+		// 장애물 생성
+		obstacleTimer++
+		if obstacleTimer > 100-min(speed*5, 50) {
+			if rng.Next() < 0.02+speed*0.005 {
+				obstacles = append(obstacles, model.JumpRunnerObstacle{
+					X:      JR_GAME_WIDTH,
+					Height: JR_OBSTACLE_HEIGHT + rng.Next()*15,
+				})
+				obstacleTimer = 0
+			}
+		}
+
+		distance += speed
+		speed += JR_SPEED_INCREMENT
+
+		if jumpRunnerCollides(playerY, obstacles) {
+			if nextJump < len(jumps) {
+				return model.JumpRunnerResult{}, ErrCollidedEarlier
+			}
+			return model.JumpRunnerResult{
+				Score:    frame + 1,
+				Distance: distance,
+				Frames:   frame + 1,
+			}, nil
+		}
+	}
+
+	return model.JumpRunnerResult{}, ErrTooManyTicks
+}
+
+// jumpRunnerGame implements Game for JumpRunner
+type jumpRunnerGame struct{}
+
+func init() {
+	RegisterGame("jump-runner", jumpRunnerGame{})
+}
+
+// Start issues the obstacle seed
+func (jumpRunnerGame) Start(session *model.GameSession, params json.RawMessage) (interface{}, error) {
+	return model.JumpRunnerStartResponse{
+		SessionID: session.ID,
+		Seed:      session.Seed,
+		StartTime: session.StartTime.UnixMilli(),
+	}, nil
+}
+
+// Apply has no in-game events; the jump timeline is sent on finish
+func (jumpRunnerGame) Apply(session *model.GameSession, event string, payload json.RawMessage) (interface{}, error) {
+	return nil, ErrUnknownEvent
+}
+
+// Finish verifies the jump timeline and ends the session
+func (jumpRunnerGame) Finish(session *model.GameSession, payload json.RawMessage) (interface{}, error) {
+	var req model.JumpRunnerFinishRequest
+	if err := decodeParams(payload, &req); err != nil {
+		return nil, err
+	}
+
+	if session.Status != "playing" {
+		return model.JumpRunnerFinishResponse{Message: "Game not in progress"}, nil
+	}
+
+	result, err := SimulateJumpRunner(session.Seed, req.Jumps)
+	if err != nil {
+		session.Status = "rejected"
+		return model.JumpRunnerFinishResponse{Message: err.Error()}, nil
+	}
+
+	if result.Frames != req.Frames {
+		session.Status = "rejected"
+		return model.JumpRunnerFinishResponse{Message: ErrFrameMismatch.Error()}, nil
+	}
+
+	// 시뮬레이션 시간보다 빨리 끝난 게임은 거부
+	elapsed := time.Since(session.StartTime).Milliseconds()
+	if float64(elapsed+JR_TIME_TOLERANCE) < float64(result.Frames)*JR_FRAME_MS {
+		session.Status = "rejected"
+		return model.JumpRunnerFinishResponse{Message: "Game finished too quickly"}, nil
+	}
+
+	session.Score = result.Score
+	session.Status = "ended"
+
+	return model.JumpRunnerFinishResponse{
+		FinalScore: result.Score,
+		Distance:   result.Distance,
+		CanSubmit:  result.Score > 0,
+	}, nil
+}
+
+// Score returns the simulated score
+func (jumpRunnerGame) Score(session *model.GameSession) int {
+	return session.Score
+}
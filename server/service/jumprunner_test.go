@@ -0,0 +1,38 @@
+package service
+
+import "testing"
+
+func TestSimulateJumpRunner(t *testing.T) {
+	// 기대값은 클라이언트(JumpRunner.jsx)와 같은 시뮬레이션 결과
+	tests := []struct {
+		name       string
+		seed       int64
+		jumps      []int
+		wantFrames int
+		wantErr    error
+	}{
+		{name: "no jumps", seed: 12345, wantFrames: 278},
+		{name: "no jumps, other seed", seed: 987654321, wantFrames: 246},
+		{name: "jump over the first obstacle", seed: 12345, jumps: []int{263}, wantFrames: 344},
+		{name: "jump in mid-air is ignored", seed: 12345, jumps: []int{263, 270}, wantFrames: 344},
+		{name: "jump after the collision", seed: 12345, jumps: []int{300}, wantErr: ErrCollidedEarlier},
+		{name: "repeated frame", seed: 12345, jumps: []int{10, 10}, wantErr: ErrInvalidJumpOrder},
+		{name: "decreasing frames", seed: 12345, jumps: []int{20, 10}, wantErr: ErrInvalidJumpOrder},
+		{name: "negative frame", seed: 12345, jumps: []int{-1}, wantErr: ErrInvalidJumpOrder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SimulateJumpRunner(tt.seed, tt.jumps)
+			if err != tt.wantErr {
+				t.Fatalf("SimulateJumpRunner() error = %v, want %v", err, tt.wantErr)
+			}
+			if got.Frames != tt.wantFrames || got.Score != tt.wantFrames {
+				t.Errorf("SimulateJumpRunner() = %+v, want %d frames", got, tt.wantFrames)
+			}
+			if tt.wantErr == nil && got.Distance <= 0 {
+				t.Errorf("SimulateJumpRunner() distance = %v", got.Distance)
+			}
+		})
+	}
+}
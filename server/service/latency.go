@@ -0,0 +1,116 @@
+package service
+
+import (
+	"time"
+
+	"mini-games/model"
+)
+
+const (
+	// 왕복 지연 측정용 핑 주기
+	PingInterval = 2 * time.Second
+
+	// 지연 보정에 반영하는 최대 왕복 시간 (핑을 일부러 늦춰 이득을 보지 못하도록)
+	MaxCompensatedRTT = 120 * time.Millisecond
+
+	// 첫 클릭 이후 다른 플레이어의 클릭을 기다리는 최대 시간
+	MaxArbitrationWindow = 150 * time.Millisecond
+
+	// 클라이언트 시간 측정 오차 허용치
+	ClockSlack = 20 * time.Millisecond
+)
+
+// compensatedReaction estimates how long after the ball appeared on the
+// player's screen they clicked it, for ranking claims. The client-reported
+// elapsed time is only trusted within what the server can observe: no later
+// than the time since spawn, and no earlier than that minus the player's
+// round trip. It also returns the server-side reaction (time since spawn minus
+// the bounded one-way latency), which is what MinReactionTime is checked
+// against since it does not depend on anything the client chose.
+func compensatedReaction(ball *model.Ball, click Click, rtt time.Duration) (reaction, serverReaction time.Duration) {
+	if rtt > MaxCompensatedRTT {
+		rtt = MaxCompensatedRTT
+	}
+	observed := time.Since(ball.SpawnedAt)
+	serverReaction = observed - rtt/2
+	earliest := observed - rtt - ClockSlack
+	if earliest < 0 {
+		earliest = 0
+	}
+
+	// 클라이언트 시각이 없으면 편도 지연만큼 보정
+	reaction = serverReaction
+	if click.Elapsed != nil {
+		reaction = time.Duration(*click.Elapsed * float64(time.Second))
+	}
+
+	if reaction < earliest {
+		reaction = earliest
+	}
+	if reaction > observed {
+		reaction = observed
+	}
+	return reaction, serverReaction
+}
+
+// arbitrationWindow is how long to collect claims on a ball after the first
+// one arrives: long enough for the slowest player's click to reach the
+// server, bounded by MaxArbitrationWindow. Caller must hold room.Mu.
+func arbitrationWindow(room *model.Room) time.Duration {
+	var window time.Duration
+	for _, p := range room.Players {
+		if p != nil && !p.Disconnected {
+			if rtt := p.RTT(); rtt > window {
+				window = rtt
+			}
+		}
+	}
+	if window > MaxArbitrationWindow {
+		window = MaxArbitrationWindow
+	}
+	return window
+}
+
+// resolveBall awards a claimed ball to the earliest compensated click once
+// its arbitration window has passed
+func (rm *RoomManager) resolveBall(room *model.Room, ball *model.Ball) {
+	room.Mu.Lock()
+	defer room.Mu.Unlock()
+
+	index := -1
+	for i, b := range room.Balls {
+		if b == ball {
+			index = i
+			break
+		}
+	}
+	if index < 0 || room.State != model.StatePlaying || len(ball.Claims) == 0 {
+		return // 라운드가 끝났거나 이미 처리됨
+	}
+
+	// 보정된 반응 시간이 가장 짧은 클릭 (같으면 먼저 도착한 클릭)
+	winner := ball.Claims[0]
+	for _, claim := range ball.Claims[1:] {
+		if claim.Reaction < winner.Reaction {
+			winner = claim
+		}
+	}
+
+	ball.Clicked = true
+	ball.ClickedBy = winner.PlayerIndex
+
+	// Calculate score (플레이어가 그 사이 나갔으면 점수 없음)
+	if p := room.Players[winner.PlayerIndex]; p != nil {
+		if ball.IsRed {
+			p.Score++
+		} else {
+			p.Score -= room.Rules.BluePenalty
+		}
+	}
+
+	sendBallResult(room, ball, winner.PlayerIndex)
+	room.Balls = append(room.Balls[:index], room.Balls[index+1:]...)
+
+	// Spawn new ball after short delay (in separate goroutine)
+	go respawnBall(room)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"sort"
+
+	"mini-games/model"
+	"mini-games/protocol"
+)
+
+// lobbyEntry builds the public listing of a room. Caller must hold room.Mu.
+func lobbyEntry(room *model.Room) model.LobbyRoom {
+	entry := model.LobbyRoom{
+		RoomCode:   room.Code,
+		Players:    playerCount(room),
+		Capacity:   room.Capacity,
+		Rules:      room.Rules,
+		State:      room.State,
+		Spectators: len(room.Spectators),
+		CreatedAt:  room.CreatedAt,
+	}
+	if host := room.Players[room.Host]; host != nil {
+		entry.Host = host.Nickname
+	}
+	return entry
+}
+
+// PublishRoom lists a room in the public lobby
+func (rm *RoomManager) PublishRoom(room *model.Room) {
+	room.Mu.Lock()
+	defer room.Mu.Unlock()
+
+	room.Public = true
+	rm.notifyLobby(room)
+}
+
+// notifyLobby refreshes a public room's listing and streams it to lobby
+// subscribers. Private rooms are ignored. Caller must hold room.Mu.
+func (rm *RoomManager) notifyLobby(room *model.Room) {
+	if !room.Public {
+		return
+	}
+	entry := lobbyEntry(room)
+
+	rm.lobbyMu.Lock()
+	defer rm.lobbyMu.Unlock()
+
+	rm.lobbyRooms[room.Code] = entry
+	for subscriber := range rm.lobbySubs {
+		subscriber.SendJSON(model.LobbyRoomMsg{Type: protocol.TypeLobbyRoom, Room: entry})
+	}
+}
+
+// unlistRoom drops a removed room from the lobby
+func (rm *RoomManager) unlistRoom(code string) {
+	rm.lobbyMu.Lock()
+	defer rm.lobbyMu.Unlock()
+
+	if _, listed := rm.lobbyRooms[code]; !listed {
+		return
+	}
+	delete(rm.lobbyRooms, code)
+	for subscriber := range rm.lobbySubs {
+		subscriber.SendJSON(model.LobbyRoomRemovedMsg{Type: protocol.TypeLobbyRoomRemoved, RoomCode: code})
+	}
+}
+
+// pruneLobby drops listings of rooms that no longer exist (e.g. updated
+// concurrently with their removal). Caller must hold rm.mu.
+func (rm *RoomManager) pruneLobby() {
+	rm.lobbyMu.Lock()
+	var gone []string
+	for code := range rm.lobbyRooms {
+		if _, exists := rm.rooms[code]; !exists {
+			gone = append(gone, code)
+		}
+	}
+	rm.lobbyMu.Unlock()
+
+	for _, code := range gone {
+		rm.unlistRoom(code)
+	}
+}
+
+// PublicRooms lists public rooms, newest first
+func (rm *RoomManager) PublicRooms() []model.LobbyRoom {
+	rm.lobbyMu.Lock()
+	defer rm.lobbyMu.Unlock()
+	return rm.publicRooms()
+}
+
+// publicRooms lists public rooms, newest first. Caller must hold rm.lobbyMu.
+func (rm *RoomManager) publicRooms() []model.LobbyRoom {
+	rooms := make([]model.LobbyRoom, 0, len(rm.lobbyRooms))
+	for _, entry := range rm.lobbyRooms {
+		rooms = append(rooms, entry)
+	}
+	sort.Slice(rooms, func(i, j int) bool {
+		return rooms[i].CreatedAt.After(rooms[j].CreatedAt)
+	})
+	return rooms
+}
+
+// SubscribeLobby sends the current public rooms to a connection and streams
+// later changes to it until UnsubscribeLobby
+func (rm *RoomManager) SubscribeLobby(player *model.Player) {
+	rm.lobbyMu.Lock()
+	defer rm.lobbyMu.Unlock()
+
+	rm.lobbySubs[player] = true
+	player.SendJSON(model.LobbyRoomsMsg{Type: protocol.TypeLobbyRooms, Rooms: rm.publicRooms()})
+}
+
+// UnsubscribeLobby stops streaming lobby changes to a connection
+func (rm *RoomManager) UnsubscribeLobby(player *model.Player) {
+	rm.lobbyMu.Lock()
+	defer rm.lobbyMu.Unlock()
+	delete(rm.lobbySubs, player)
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"log"
+	"math"
+	"time"
+
+	"mini-games/model"
+	"mini-games/protocol"
+)
+
+const (
+	// 매칭 시도 주기
+	MatchInterval = 1 * time.Second
+
+	// 레이팅 매칭 허용 차이 (대기 1초마다 MatchRatingWiden만큼 확장)
+	MatchRatingWindow = 100.0
+	MatchRatingWiden  = 50.0
+
+	DefaultRating = 1500.0
+)
+
+// QueueTicket is a player's place in the matchmaking queue
+type QueueTicket struct {
+	Player   *model.Player
+	Rated    bool // true면 레이팅이 비슷한 상대와만 매칭
+	Rating   float64
+	JoinedAt time.Time
+
+	room       *model.Room // 매칭 완료 시 생성된 방
+	tournament *tournament // 토너먼트 체크인이면 설정
+}
+
+// Enqueue places a player in the matchmaking queue
+func (rm *RoomManager) Enqueue(player *model.Player, rated bool) (*QueueTicket, error) {
+	ticket := &QueueTicket{
+		Player:   player,
+		Rated:    rated,
+		Rating:   DefaultRating,
+		JoinedAt: time.Now(),
+	}
+	if rated && rm.RatingLookup != nil {
+		ticket.Rating = rm.RatingLookup(player.Nickname)
+	}
+
+	rm.queueMu.Lock()
+	for _, t := range rm.queue {
+		if t.Player == player {
+			rm.queueMu.Unlock()
+			return nil, ErrAlreadyQueued
+		}
+	}
+	rm.queue = append(rm.queue, ticket)
+	rm.queueMu.Unlock()
+
+	rm.matchQueue()
+	return ticket, nil
+}
+
+// CancelQueue removes a waiting ticket (matchmaking or tournament check-in).
+// It returns false if the ticket is no longer waiting (already matched or
+// cancelled).
+func (rm *RoomManager) CancelQueue(ticket *QueueTicket) bool {
+	if ticket.tournament != nil {
+		return rm.cancelCheckIn(ticket)
+	}
+
+	rm.queueMu.Lock()
+	defer rm.queueMu.Unlock()
+
+	for i, t := range rm.queue {
+		if t == ticket {
+			rm.queue = append(rm.queue[:i], rm.queue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// TicketRoom returns the room a ticket was matched into, or nil if it is
+// still waiting
+func (rm *RoomManager) TicketRoom(ticket *QueueTicket) *model.Room {
+	rm.queueMu.Lock()
+	defer rm.queueMu.Unlock()
+	return ticket.room
+}
+
+// canMatch reports whether two queued players may be paired
+func canMatch(a, b *QueueTicket, now time.Time) bool {
+	if a.Rated != b.Rated {
+		return false
+	}
+	if !a.Rated {
+		return true
+	}
+
+	// 오래 기다릴수록 허용 범위 확장
+	waited := math.Max(now.Sub(a.JoinedAt).Seconds(), now.Sub(b.JoinedAt).Seconds())
+	window := MatchRatingWindow + MatchRatingWiden*waited
+	return math.Abs(a.Rating-b.Rating) <= window
+}
+
+// queueMatch is a pairing made by matchQueue, captured under the queue lock
+type queueMatch struct {
+	room    *model.Room
+	players [2]*model.Player
+	tokens  [2]string
+}
+
+// matchQueue pairs waiting players into new rooms and reports the queue
+// status to everyone still waiting
+func (rm *RoomManager) matchQueue() {
+	rm.queueMu.Lock()
+
+	now := time.Now()
+	matched := make(map[*QueueTicket]bool)
+	var pairs []queueMatch
+
+	// 먼저 들어온 순서대로 상대 탐색
+	for i, a := range rm.queue {
+		if matched[a] {
+			continue
+		}
+		for _, b := range rm.queue[i+1:] {
+			if matched[b] || !canMatch(a, b, now) {
+				continue
+			}
+
+			room, code := rm.CreateRoom(a.Player, DefaultRoomCapacity, DefaultRoomRules())
+			if _, err := rm.JoinRoom(code, b.Player); err != nil {
+				log.Printf("Matchmaking failed to join room %s: %v", code, err)
+				rm.RemoveRoom(code)
+				continue
+			}
+
+			matched[a], matched[b] = true, true
+			a.room, b.room = room, room
+			rm.recordWait(now.Sub(a.JoinedAt))
+			rm.recordWait(now.Sub(b.JoinedAt))
+			pairs = append(pairs, queueMatch{
+				room:    room,
+				players: [2]*model.Player{a.Player, b.Player},
+				tokens:  [2]string{a.Player.ReconnectToken, b.Player.ReconnectToken},
+			})
+			break
+		}
+	}
+
+	waiting := make([]*QueueTicket, 0, len(rm.queue))
+	for _, t := range rm.queue {
+		if !matched[t] {
+			waiting = append(waiting, t)
+		}
+	}
+	rm.queue = waiting
+	avgWait := rm.avgWait
+	rm.queueMu.Unlock()
+
+	// 잠금 밖에서는 방의 자리가 비었을 수 있으므로 짝지을 때 잡아 둔 값만 사용
+	for _, pair := range pairs {
+		for i, p := range pair.players {
+			p.SendJSON(model.MatchFoundMsg{
+				Type:           protocol.TypeMatchFound,
+				RoomCode:       pair.room.Code,
+				PlayerIndex:    i,
+				Nickname:       pair.players[1-i].Nickname,
+				ReconnectToken: pair.tokens[i],
+			})
+		}
+		go rm.StartGame(pair.room)
+	}
+
+	for i, t := range waiting {
+		waited := now.Sub(t.JoinedAt)
+		t.Player.SendJSON(model.QueueStatusMsg{
+			Type:          protocol.TypeQueueStatus,
+			QueueSize:     len(waiting),
+			Position:      i + 1,
+			Waited:        waited.Seconds(),
+			EstimatedWait: math.Max((avgWait - waited).Seconds(), 0),
+		})
+	}
+}
+
+// recordWait updates the moving average of matchmaking wait time.
+// Caller must hold rm.queueMu.
+func (rm *RoomManager) recordWait(wait time.Duration) {
+	if rm.avgWait == 0 {
+		rm.avgWait = wait
+		return
+	}
+	rm.avgWait = (rm.avgWait*4 + wait) / 5
+}
+
+// matchRoutine periodically retries matching so rating windows can widen
+func (rm *RoomManager) matchRoutine() {
+	ticker := time.NewTicker(MatchInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		rm.matchQueue()
+	}
+}
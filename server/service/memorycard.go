@@ -0,0 +1,216 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"mini-games/model"
+)
+
+const (
+	// 연속 매칭 시 콤보당 추가 점수
+	MEMORY_COMBO_BONUS = 10
+)
+
+// MemoryCard 레벨 설정 (클라이언트와 동일해야 함)
+var memoryCardLevels = map[string]model.MemoryCardLevel{
+	"easy":   {Cols: 4, Rows: 3, Pairs: 6, TimeLimit: 60, Bonus: 0},
+	"medium": {Cols: 4, Rows: 4, Pairs: 8, TimeLimit: 90, Bonus: 100},
+	"hard":   {Cols: 5, Rows: 4, Pairs: 10, TimeLimit: 120, Bonus: 200},
+}
+
+// shuffleMemoryDeck builds a shuffled deck of face indexes. The deck is
+// hidden state, so it is drawn from crypto/rand rather than a seeded PRNG
+// whose 32-bit state a client could search.
+func shuffleMemoryDeck(pairs int) ([]int, error) {
+	faces := make([]int, 0, pairs*2)
+	for i := 0; i < pairs; i++ {
+		faces = append(faces, i, i)
+	}
+
+	// Fisher-Yates 셔플
+	for i := len(faces) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return nil, err
+		}
+		faces[i], faces[j.Int64()] = faces[j.Int64()], faces[i]
+	}
+	return faces, nil
+}
+
+// calculateMemoryCardScore computes the final score of a cleared board
+func calculateMemoryCardScore(config model.MemoryCardLevel, state *model.MemoryCardState, elapsed time.Duration) int {
+	// 점수 계산: (남은 시간 × 10) + 레벨 보너스 - (이동 횟수 × 2) + 콤보 보너스
+	timeLeft := config.TimeLimit - int(elapsed.Seconds())
+	if timeLeft < 0 {
+		timeLeft = 0
+	}
+	score := timeLeft*10 + config.Bonus - state.Moves*2 + state.ComboSum
+	if score < 0 {
+		score = 0
+	}
+	return score
+}
+
+// memoryCardGame implements Game for MemoryCard
+type memoryCardGame struct{}
+
+func init() {
+	RegisterGame("memory-card", memoryCardGame{})
+}
+
+// Start shuffles the deck on the server and returns only card IDs
+func (memoryCardGame) Start(session *model.GameSession, params json.RawMessage) (interface{}, error) {
+	var req model.MemoryCardStartRequest
+	if err := decodeParams(params, &req); err != nil {
+		return nil, err
+	}
+	config, ok := memoryCardLevels[req.Level]
+	if !ok {
+		return nil, ErrInvalidLevel
+	}
+
+	faces, err := shuffleMemoryDeck(config.Pairs)
+	if err != nil {
+		return nil, err
+	}
+
+	session.Level = req.Level
+	session.State = &model.MemoryCardState{
+		Faces:   faces,
+		Matched: make([]bool, config.Pairs*2),
+		Flipped: -1,
+	}
+
+	cards := make([]int, config.Pairs*2)
+	for i := range cards {
+		cards[i] = i
+	}
+
+	return model.MemoryCardStartResponse{
+		SessionID: session.ID,
+		Cards:     cards,
+		Cols:      config.Cols,
+		Rows:      config.Rows,
+		TimeLimit: config.TimeLimit,
+		StartTime: session.StartTime.UnixMilli(),
+	}, nil
+}
+
+// Apply handles the "flip" event
+func (memoryCardGame) Apply(session *model.GameSession, event string, payload json.RawMessage) (interface{}, error) {
+	if event != "flip" {
+		return nil, ErrUnknownEvent
+	}
+	var req model.MemoryCardFlipRequest
+	if err := decodeParams(payload, &req); err != nil {
+		return nil, err
+	}
+	return flipMemoryCard(session, req.CardID), nil
+}
+
+// Finish ends the session; giving up before clearing the board scores 0
+func (memoryCardGame) Finish(session *model.GameSession, payload json.RawMessage) (interface{}, error) {
+	if session.Status == "playing" {
+		session.Status = "ended"
+		session.Score = 0
+	}
+
+	return model.EndGameResponse{
+		FinalScore: session.Score,
+		CanSubmit:  session.Status == "ended" && session.Score > 0,
+	}, nil
+}
+
+// Score returns the score computed when the board was cleared
+func (memoryCardGame) Score(session *model.GameSession) int {
+	return session.Score
+}
+
+// NewState returns an empty deck state for session stores
+func (memoryCardGame) NewState() interface{} {
+	return &model.MemoryCardState{}
+}
+
+// flipMemoryCard reveals a card and resolves pairs
+func flipMemoryCard(session *model.GameSession, cardID int) model.MemoryCardFlipResponse {
+	if session.Status != "playing" {
+		return model.MemoryCardFlipResponse{Valid: false, Message: "Game not in progress"}
+	}
+
+	state := session.State.(*model.MemoryCardState)
+	config := memoryCardLevels[session.Level]
+
+	// 시간 초과
+	elapsed := time.Since(session.StartTime)
+	if elapsed >= time.Duration(config.TimeLimit)*time.Second {
+		session.Status = "ended"
+		session.Score = 0
+		return model.MemoryCardFlipResponse{Valid: false, GameOver: true, Moves: state.Moves, Message: "Time over"}
+	}
+
+	if cardID < 0 || cardID >= len(state.Faces) {
+		return model.MemoryCardFlipResponse{Valid: false, Message: "Invalid card"}
+	}
+
+	if state.Matched[cardID] || state.Flipped == cardID {
+		return model.MemoryCardFlipResponse{Valid: false, Message: "Card already revealed"}
+	}
+
+	face := state.Faces[cardID]
+
+	// 첫 번째 카드
+	if state.Flipped == -1 {
+		state.Flipped = cardID
+		return model.MemoryCardFlipResponse{
+			Valid: true,
+			Face:  face,
+			Moves: state.Moves,
+			Combo: state.Combo,
+		}
+	}
+
+	// 두 번째 카드
+	first := state.Flipped
+	state.Flipped = -1
+	state.Moves++
+
+	matched := state.Faces[first] == face
+	if matched {
+		state.Matched[first] = true
+		state.Matched[cardID] = true
+		state.Pairs++
+		state.Combo++
+		if state.Combo > state.MaxCombo {
+			state.MaxCombo = state.Combo
+		}
+		if state.Combo >= 2 {
+			state.ComboSum += (state.Combo - 1) * MEMORY_COMBO_BONUS
+		}
+	} else {
+		state.Combo = 0
+	}
+
+	response := model.MemoryCardFlipResponse{
+		Valid:      true,
+		Face:       face,
+		Matched:    matched,
+		PairCardID: &first,
+		Moves:      state.Moves,
+		Combo:      state.Combo,
+	}
+
+	// 모든 쌍을 찾으면 게임 종료
+	if state.Pairs == config.Pairs {
+		session.Score = calculateMemoryCardScore(config, state, elapsed)
+		session.Status = "ended"
+		response.GameOver = true
+		response.Won = true
+		response.FinalScore = session.Score
+	}
+
+	return response
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"mini-games/database"
+	"mini-games/model"
+)
+
+var (
+	ErrReplayDuplicateBall = errors.New("Ball recorded more than once")
+	ErrReplayMissingBall   = errors.New("Replay skips a ball")
+	ErrReplayAfterGameOver = errors.New("Events recorded after game over")
+	ErrReplayHashMismatch  = errors.New("Event log does not match session")
+	ErrReplayScoreMismatch = errors.New("Replay score does not match session")
+	ErrReplayNotFound      = errors.New("Replay not found")
+)
+
+func clickEvent(ballIndex int, clickTimeMs int64) string {
+	return fmt.Sprintf("c:%d:%d", ballIndex, clickTimeMs)
+}
+
+func missEvent(ballIndex int) string {
+	return fmt.Sprintf("m:%d", ballIndex)
+}
+
+// chainSpeedClickHash appends an event to the running hash chain
+func chainSpeedClickHash(prev string, event string) string {
+	sum := sha256.Sum256([]byte(prev + "|" + event))
+	return hex.EncodeToString(sum[:])
+}
+
+// speedClickReplayHash recomputes the hash chain of an event log.
+// Events are chained in ball order, the same order the server accepted them.
+func speedClickReplayHash(replay model.SpeedClickReplay) (string, error) {
+	events := make(map[int]string, len(replay.Clicks)+len(replay.Misses))
+	for _, click := range replay.Clicks {
+		if _, exists := events[click.BallIndex]; exists {
+			return "", ErrReplayDuplicateBall
+		}
+		events[click.BallIndex] = clickEvent(click.BallIndex, click.ClickTime)
+	}
+	for _, index := range replay.Misses {
+		if _, exists := events[index]; exists {
+			return "", ErrReplayDuplicateBall
+		}
+		events[index] = missEvent(index)
+	}
+
+	hash := ""
+	for index := 0; index < len(events); index++ {
+		event, ok := events[index]
+		if !ok {
+			return "", ErrReplayMissingBall
+		}
+		hash = chainSpeedClickHash(hash, event)
+	}
+	return hash, nil
+}
+
+// VerifySpeedClickReplay deterministically recomputes the final score of a
+// SpeedClick game from its seed and recorded clicks/misses
+func VerifySpeedClickReplay(seed int64, events model.SpeedClickReplay) (int, error) {
+	clicks := make(map[int]model.ClickRecord, len(events.Clicks))
+	for _, click := range events.Clicks {
+		if _, exists := clicks[click.BallIndex]; exists {
+			return 0, ErrReplayDuplicateBall
+		}
+		clicks[click.BallIndex] = click
+	}
+
+	missed := make(map[int]bool, len(events.Misses))
+	for _, index := range events.Misses {
+		if _, clicked := clicks[index]; clicked || missed[index] {
+			return 0, ErrReplayDuplicateBall
+		}
+		missed[index] = true
+	}
+
+	score := 0
+	lives := SPEEDCLICK_LIVES
+	var spawnTime int64
+
+	// 공은 0번부터 순서대로 클릭 또는 놓침 중 하나로 처리되어야 함
+	total := len(clicks) + len(missed)
+	for index := 0; index < total; index++ {
+		if lives <= 0 {
+			return 0, ErrReplayAfterGameOver
+		}
+
+		ball := GenerateBall(seed, index, score, spawnTime)
+
+		if click, ok := clicks[index]; ok {
+			if message := checkClickTime(ball, click.ClickTime); message != "" {
+				return 0, errors.New(message)
+			}
+			if ball.IsRed {
+				score += clickPoints(ball, click.ClickTime)
+			} else {
+				lives--
+			}
+		} else if missed[index] {
+			if ball.IsRed {
+				lives--
+			}
+		} else {
+			return 0, ErrReplayMissingBall
+		}
+
+		spawnTime = ball.SpawnTime + ball.Duration
+	}
+
+	return score, nil
+}
+
+// SaveSpeedClickReplay stores a SpeedClick event log for a score
+func SaveSpeedClickReplay(scoreID int64, replay model.SpeedClickReplay) error {
+	clicks, err := json.Marshal(replay.Clicks)
+	if err != nil {
+		return err
+	}
+	misses, err := json.Marshal(replay.Misses)
+	if err != nil {
+		return err
+	}
+
+	_, err = database.DB.Exec(
+		"INSERT INTO speedclick_replays (score_id, seed, clicks, misses) VALUES (?, ?, ?, ?)",
+		scoreID, replay.Seed, string(clicks), string(misses),
+	)
+	return err
+}
+
+// VerifyStoredSpeedClickScores re-verifies stored SpeedClick scores against
+// their replays without changing anything (see RecordSpeedClickVerifications).
+// scoreID > 0 verifies a single score, otherwise the latest limit scores.
+func VerifyStoredSpeedClickScores(scoreID int64, limit int) ([]model.ReplayVerification, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	query := `SELECT s.id, s.nickname, s.score, r.seed, r.clicks, r.misses
+		 FROM speedclick_replays r
+		 JOIN scores s ON s.id = r.score_id`
+	args := []interface{}{}
+	if scoreID > 0 {
+		query += " WHERE r.score_id = ?"
+		args = append(args, scoreID)
+	}
+	query += " ORDER BY r.score_id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []model.ReplayVerification
+	for rows.Next() {
+		var v model.ReplayVerification
+		var replay model.SpeedClickReplay
+		var clicks, misses string
+		if err := rows.Scan(&v.ScoreID, &v.Nickname, &v.StoredScore, &replay.Seed, &clicks, &misses); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		if err := json.Unmarshal([]byte(clicks), &replay.Clicks); err != nil {
+			v.Error = "Corrupted click log"
+		} else if err := json.Unmarshal([]byte(misses), &replay.Misses); err != nil {
+			v.Error = "Corrupted miss log"
+		} else if score, err := VerifySpeedClickReplay(replay.Seed, replay); err != nil {
+			v.Error = err.Error()
+		} else {
+			v.ReplayScore = score
+			v.Valid = score == v.StoredScore
+		}
+		results = append(results, v)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// RecordSpeedClickVerifications stores verification results, flagging the
+// scores whose replay does not reproduce the stored score
+func RecordSpeedClickVerifications(results []model.ReplayVerification) error {
+	for _, v := range results {
+		flagged := 0
+		if !v.Valid {
+			flagged = 1
+		}
+		if _, err := database.DB.Exec(
+			"UPDATE speedclick_replays SET flagged = ?, verified_at = CURRENT_TIMESTAMP WHERE score_id = ?",
+			flagged, v.ScoreID,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveBattleReplay stores the event stream of a finished battle and returns
+// its replay ID. matchID links it to the rated match, or is 0.
+func SaveBattleReplay(replay *model.BattleReplay, matchID int64) (int64, error) {
+	replay.MatchID = matchID
+	data, err := replay.Encode()
+	if err != nil {
+		return 0, err
+	}
+
+	var match interface{}
+	if matchID != 0 {
+		match = matchID
+	}
+	result, err := database.DB.Exec(
+		"INSERT INTO battle_replays (room_code, match_id, events) VALUES (?, ?, ?)",
+		replay.RoomCode, match, string(data),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// GetBattleReplay returns the stored replay JSON by replay ID
+func GetBattleReplay(id int64) ([]byte, error) {
+	return queryBattleReplay("SELECT events FROM battle_replays WHERE id = ?", id)
+}
+
+// GetBattleReplayByMatch returns the stored replay JSON of a rated match
+func GetBattleReplayByMatch(matchID int64) ([]byte, error) {
+	return queryBattleReplay("SELECT events FROM battle_replays WHERE match_id = ?", matchID)
+}
+
+// GetBattleReplaysByRoom returns the IDs of a room's replays, newest first
+// (room codes are reused once a room closes)
+func GetBattleReplaysByRoom(roomCode string, limit int) ([]int64, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	rows, err := database.DB.Query(
+		"SELECT id FROM battle_replays WHERE room_code = ? ORDER BY id DESC LIMIT ?",
+		roomCode, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// queryBattleReplay loads one replay's JSON
+func queryBattleReplay(query string, arg interface{}) ([]byte, error) {
+	var data string
+	err := database.DB.QueryRow(query, arg).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, ErrReplayNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(data), nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"testing"
+
+	"mini-games/model"
+)
+
+// speedClickLog plays n balls: red balls are clicked halfway through their
+// lifetime, blue balls are left to expire
+func speedClickLog(seed int64, n int) model.SpeedClickReplay {
+	replay := model.SpeedClickReplay{Seed: seed}
+	score := 0
+	var spawnTime int64
+	for index := 0; index < n; index++ {
+		ball := GenerateBall(seed, index, score, spawnTime)
+		if ball.IsRed {
+			clickTime := ball.SpawnTime + ball.Duration/2
+			points := clickPoints(ball, clickTime)
+			score += points
+			replay.Clicks = append(replay.Clicks, model.ClickRecord{BallIndex: index, ClickTime: clickTime, Valid: true, Points: points})
+		} else {
+			replay.Misses = append(replay.Misses, index)
+		}
+		spawnTime = ball.SpawnTime + ball.Duration
+	}
+	return replay
+}
+
+func TestVerifySpeedClickReplay(t *testing.T) {
+	const seed = 20240601
+	valid := speedClickLog(seed, 40)
+
+	first := GenerateBall(seed, 0, 0, 0)
+
+	tests := []struct {
+		name    string
+		replay  model.SpeedClickReplay
+		want    int
+		wantErr string
+	}{
+		{name: "empty game", replay: model.SpeedClickReplay{}, want: 0},
+		{name: "clicks every red ball", replay: valid, want: 210}, // 클라이언트와 같은 공 생성 기준 값
+		{
+			name:   "instant click on the first ball scores the most",
+			replay: model.SpeedClickReplay{Clicks: []model.ClickRecord{{BallIndex: 0, ClickTime: first.SpawnTime}}},
+			want:   clickPoints(first, first.SpawnTime),
+		},
+		{
+			name:   "three missed red balls end the game",
+			replay: model.SpeedClickReplay{Misses: missesUntilReds(seed, 3)},
+			want:   0,
+		},
+		{
+			name:    "events after game over",
+			replay:  model.SpeedClickReplay{Misses: missesUntilReds(seed, 4)},
+			wantErr: ErrReplayAfterGameOver.Error(),
+		},
+		{
+			name:    "ball clicked twice",
+			replay:  model.SpeedClickReplay{Clicks: []model.ClickRecord{{BallIndex: 0, ClickTime: first.SpawnTime}, {BallIndex: 0, ClickTime: first.SpawnTime + 10}}},
+			wantErr: ErrReplayDuplicateBall.Error(),
+		},
+		{
+			name:    "ball both clicked and missed",
+			replay:  model.SpeedClickReplay{Clicks: []model.ClickRecord{{BallIndex: 0, ClickTime: first.SpawnTime}}, Misses: []int{0}},
+			wantErr: ErrReplayDuplicateBall.Error(),
+		},
+		{
+			name:    "ball missed twice",
+			replay:  model.SpeedClickReplay{Misses: []int{0, 0}},
+			wantErr: ErrReplayDuplicateBall.Error(),
+		},
+		{
+			name:    "skipped ball",
+			replay:  model.SpeedClickReplay{Misses: []int{0, 2}},
+			wantErr: ErrReplayMissingBall.Error(),
+		},
+		{
+			name:    "click before the ball appears",
+			replay:  model.SpeedClickReplay{Clicks: []model.ClickRecord{{BallIndex: 0, ClickTime: first.SpawnTime - CLICK_TIME_TOLERANCE - 1}}},
+			wantErr: "Click too early",
+		},
+		{
+			name:    "click after the ball expires",
+			replay:  model.SpeedClickReplay{Clicks: []model.ClickRecord{{BallIndex: 0, ClickTime: first.SpawnTime + first.Duration + CLICK_TIME_TOLERANCE + 1}}},
+			wantErr: "Click too late",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifySpeedClickReplay(seed, tt.replay)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("VerifySpeedClickReplay() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifySpeedClickReplay() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("VerifySpeedClickReplay() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// missesUntilReds returns a miss log covering the first reds red balls
+// (blue balls in between are missed too, which costs nothing)
+func missesUntilReds(seed int64, reds int) []int {
+	var misses []int
+	var spawnTime int64
+	for index := 0; reds > 0; index++ {
+		ball := GenerateBall(seed, index, 0, spawnTime)
+		misses = append(misses, index)
+		if ball.IsRed {
+			reds--
+		}
+		spawnTime = ball.SpawnTime + ball.Duration
+	}
+	return misses
+}
@@ -1,34 +1,78 @@
 package service
 
 import (
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"math"
 	"math/rand"
+	"sort"
 	"sync"
 	"time"
 
 	"mini-games/model"
+	"mini-games/protocol"
 )
 
 const (
-	GameWidth    = 1200
-	GameHeight   = 800
-	GameDuration = 10.0 // seconds
-	RoomTimeout  = 5 * time.Minute
-	PostGameTimeout = 2 * time.Minute
+	GameWidth             = 1200
+	GameHeight            = 800
+	GameDuration          = 10.0 // seconds
+	RoomTimeout           = 5 * time.Minute
+	PostGameTimeout       = 2 * time.Minute
+	DefaultReconnectGrace = 15 * time.Second
+	DefaultMaxSpectators  = 16
+
+	// 방 인원 (기본 1:1)
+	MinRoomCapacity     = 2
+	MaxRoomCapacity     = 8
+	DefaultRoomCapacity = 2
+
+	// 시리즈 라운드 사이 대기 시간
+	SeriesIntermission = 3 * time.Second
 )
 
 // RoomManager manages all battle rooms
 type RoomManager struct {
 	rooms map[string]*model.Room
 	mu    sync.RWMutex
+
+	// ReconnectGrace is how long a dropped player's slot is held
+	ReconnectGrace time.Duration
+	// MaxSpectators caps read-only connections per room
+	MaxSpectators int
+	// RatingLookup returns a player's rating for rated matchmaking
+	RatingLookup func(nickname string) float64
+	// ChatFilter screens chat text before broadcast (nil: no filtering)
+	ChatFilter ChatFilter
+
+	queue   []*QueueTicket // 매칭 대기열
+	avgWait time.Duration  // 최근 매칭 대기 시간 평균
+	queueMu sync.Mutex
+
+	tournaments      map[int64]*tournament
+	nextTournamentID int64
+	tournamentsMu    sync.Mutex
+
+	lobbyRooms map[string]model.LobbyRoom // 공개 방 목록
+	lobbySubs  map[*model.Player]bool     // lobby_subscribe 연결
+	lobbyMu    sync.Mutex
 }
 
 // NewRoomManager creates a new room manager
 func NewRoomManager() *RoomManager {
 	rm := &RoomManager{
-		rooms: make(map[string]*model.Room),
+		rooms:          make(map[string]*model.Room),
+		ReconnectGrace: DefaultReconnectGrace,
+		MaxSpectators:  DefaultMaxSpectators,
+		RatingLookup:   GetBattleRating,
+		tournaments:    make(map[int64]*tournament),
+		lobbyRooms:     make(map[string]model.LobbyRoom),
+		lobbySubs:      make(map[*model.Player]bool),
 	}
 	// Start cleanup goroutine
 	go rm.cleanupRoutine()
+	go rm.matchRoutine()
 	return rm
 }
 
@@ -42,8 +86,13 @@ func generateRoomCode() string {
 	return string(code)
 }
 
-// CreateRoom creates a new room and returns the room code
-func (rm *RoomManager) CreateRoom(player *model.Player) (*model.Room, string) {
+// CreateRoom creates a new room with the given number of player slots and
+// validated rules, and returns the room code. The creator is the host.
+func (rm *RoomManager) CreateRoom(player *model.Player, capacity int, rules model.RoomRules) (*model.Room, string) {
+	if capacity < MinRoomCapacity || capacity > MaxRoomCapacity {
+		capacity = DefaultRoomCapacity
+	}
+
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
 
@@ -58,10 +107,13 @@ func (rm *RoomManager) CreateRoom(player *model.Player) (*model.Room, string) {
 
 	room := &model.Room{
 		Code:      code,
-		Players:   [2]*model.Player{player, nil},
+		Players:   make([]*model.Player, capacity),
+		Capacity:  capacity,
+		Host:      0,
 		State:     model.StateWaiting,
 		CreatedAt: time.Now(),
-		Duration:  GameDuration,
+		Duration:  rules.Duration,
+		Rules:     rules,
 		StopGame:  make(chan struct{}),
 	}
 
@@ -69,38 +121,71 @@ func (rm *RoomManager) CreateRoom(player *model.Player) (*model.Room, string) {
 	player.Score = 0
 	player.Ready = false
 	player.LastActive = time.Now()
+	player.ReconnectToken = generateSessionID()
+	player.Disconnected = false
 
+	room.Players[0] = player
 	rm.rooms[code] = room
 	return room, code
 }
 
-// JoinRoom joins an existing room
+// JoinRoom joins an existing room in its first free slot
 func (rm *RoomManager) JoinRoom(code string, player *model.Player) (*model.Room, error) {
-	rm.mu.Lock()
-	defer rm.mu.Unlock()
-
-	room, exists := rm.rooms[code]
-	if !exists {
+	room := rm.GetRoom(code)
+	if room == nil {
 		return nil, ErrRoomNotFound
 	}
 
+	room.Mu.Lock()
+	defer room.Mu.Unlock()
+
 	if room.State != model.StateWaiting {
 		return nil, ErrRoomNotAvailable
 	}
 
-	if room.Players[1] != nil {
+	slot := -1
+	for i, p := range room.Players {
+		if p == nil {
+			slot = i
+			break
+		}
+	}
+	if slot < 0 {
 		return nil, ErrRoomFull
 	}
 
-	player.Index = 1
+	player.Index = slot
 	player.Score = 0
 	player.Ready = false
 	player.LastActive = time.Now()
+	player.ReconnectToken = generateSessionID()
+	player.Disconnected = false
+
+	room.Players[slot] = player
+
+	// 1:1 방의 상대에게는 핸들러가 opponent_joined를 보냄
+	joined := model.PlayerJoinedMsg{Type: protocol.TypePlayerJoined, PlayerIndex: slot, Nickname: player.Nickname}
+	if room.Capacity > 2 {
+		sendToOthers(room, slot, joined)
+	} else {
+		sendToSpectators(room, joined)
+	}
+	rm.notifyLobby(room)
 
-	room.Players[1] = player
 	return room, nil
 }
 
+// playerCount returns the number of occupied slots. Caller must hold room.Mu.
+func playerCount(room *model.Room) int {
+	count := 0
+	for _, p := range room.Players {
+		if p != nil {
+			count++
+		}
+	}
+	return count
+}
+
 // GetRoom returns a room by code
 func (rm *RoomManager) GetRoom(code string) *model.Room {
 	rm.mu.RLock()
@@ -112,7 +197,7 @@ func (rm *RoomManager) GetRoom(code string) *model.Room {
 func (rm *RoomManager) RemoveRoom(code string) {
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
-	
+
 	if room, exists := rm.rooms[code]; exists {
 		// Signal game loop to stop
 		select {
@@ -121,6 +206,7 @@ func (rm *RoomManager) RemoveRoom(code string) {
 			close(room.StopGame)
 		}
 		delete(rm.rooms, code)
+		rm.unlistRoom(code)
 	}
 }
 
@@ -139,45 +225,345 @@ func (rm *RoomManager) RemovePlayerFromRoom(code string, playerIndex int) {
 
 	room.Players[playerIndex] = nil
 
-	// Notify other player
-	otherIndex := 1 - playerIndex
-	if room.Players[otherIndex] != nil {
-		room.Players[otherIndex].SendJSON(model.OpponentLeftMsg{Type: "opponent_left"})
+	// Notify other players
+	var leftMsg interface{} = model.PlayerLeftMsg{Type: protocol.TypePlayerLeft, PlayerIndex: playerIndex}
+	if room.Capacity == 2 {
+		leftMsg = model.OpponentLeftMsg{Type: protocol.TypeOpponentLeft}
 	}
+	for _, p := range room.Players {
+		if p != nil {
+			p.SendJSON(leftMsg)
+		}
+	}
+	sendToSpectators(room, model.PlayerLeftMsg{Type: protocol.TypePlayerLeft, PlayerIndex: playerIndex})
 
-	// Check if room is empty
-	if room.Players[0] == nil && room.Players[1] == nil {
+	remaining := playerCount(room)
+
+	// Check if room is empty (봇만 남아도 방을 닫음)
+	if humanCount(room) == 0 {
+		stopGame(room)
+		sendToSpectators(room, model.RoomClosedMsg{Type: protocol.TypeRoomClosed})
 		rm.RemoveRoom(code)
-	} else {
-		// Reset room to waiting state if game was in progress
-		if room.State == model.StatePlaying || room.State == model.StateCountdown {
-			select {
-			case <-room.StopGame:
-			default:
-				close(room.StopGame)
+		return
+	}
+
+	// 방장이 나가면 가장 앞 슬롯의 플레이어에게 위임
+	if room.Host == playerIndex {
+		for i, p := range room.Players {
+			if p != nil {
+				room.Host = i
+				break
+			}
+		}
+		broadcast(room, model.HostChangedMsg{Type: protocol.TypeHostChanged, Host: room.Host})
+	}
+
+	// 토너먼트 경기 중 상대가 나가면 남은 선수의 부전승
+	if room.TournamentID != 0 && remaining == 1 && room.State != model.StateFinished {
+		for _, p := range room.Players {
+			if p != nil {
+				ranked := []model.Standing{{PlayerIndex: p.Index, Nickname: p.Nickname, Score: p.Score, Place: 1}}
+				go rm.reportTournamentResult(room.TournamentID, room.TournamentMatch, ranked)
 			}
-			room.StopGame = make(chan struct{})
 		}
-		room.State = model.StateWaiting
 	}
+
+	// 남은 인원으로 계속할 수 있으면 진행 중인 게임 유지
+	if remaining >= 2 {
+		if room.State == model.StateFinished {
+			rm.startRematchIfReady(room)
+		}
+		rm.notifyLobby(room)
+		return
+	}
+
+	// Reset room to waiting state if game was in progress
+	stopGame(room)
+	room.State = model.StateWaiting
+	rm.notifyLobby(room)
+}
+
+// stopGame stops a game in progress and arms a fresh stop channel.
+// Caller must hold room.Mu.
+func stopGame(room *model.Room) {
+	if room.State == model.StatePlaying || room.State == model.StateCountdown || room.State == model.StateIntermission {
+		select {
+		case <-room.StopGame:
+		default:
+			close(room.StopGame)
+		}
+		room.StopGame = make(chan struct{})
+	}
+}
+
+// DisconnectPlayer holds a dropped player's slot for the reconnect grace
+// period. The game keeps running; the slot is released if the player does
+// not rejoin in time.
+func (rm *RoomManager) DisconnectPlayer(code string, player *model.Player, conn *model.Conn) {
+	// 이미 새 연결로 재접속한 경우
+	if !player.Detach(conn) {
+		return
+	}
+
+	room := rm.GetRoom(code)
+	if room == nil {
+		return
+	}
+
+	room.Mu.Lock()
+	if room.Players[player.Index] != player {
+		room.Mu.Unlock()
+		return
+	}
+	if rm.ReconnectGrace <= 0 {
+		room.Mu.Unlock()
+		rm.RemovePlayerFromRoom(code, player.Index)
+		return
+	}
+
+	disconnectedAt := time.Now()
+	player.Disconnected = true
+	player.DisconnectedAt = disconnectedAt
+
+	sendToOthers(room, player.Index, model.OpponentDisconnectedMsg{
+		Type:        protocol.TypeOpponentDisconnected,
+		PlayerIndex: player.Index,
+		Grace:       rm.ReconnectGrace.Seconds(),
+	})
+	room.Mu.Unlock()
+
+	time.AfterFunc(rm.ReconnectGrace, func() {
+		room.Mu.RLock()
+		expired := room.Players[player.Index] == player && player.Disconnected &&
+			player.DisconnectedAt.Equal(disconnectedAt)
+		room.Mu.RUnlock()
+
+		if expired && rm.GetRoom(code) == room {
+			rm.RemovePlayerFromRoom(code, player.Index)
+		}
+	})
+}
+
+// RejoinRoom re-attaches a new connection to the player holding the
+// reconnect token and replays the current game state to it
+func (rm *RoomManager) RejoinRoom(code string, token string, conn *model.Conn) (*model.Room, *model.Player, error) {
+	room := rm.GetRoom(code)
+	if room == nil {
+		return nil, nil, ErrRoomNotFound
+	}
+
+	room.Mu.Lock()
+	defer room.Mu.Unlock()
+
+	var player *model.Player
+	for _, p := range room.Players {
+		if p != nil && token != "" && subtle.ConstantTimeCompare([]byte(p.ReconnectToken), []byte(token)) == 1 {
+			player = p
+			break
+		}
+	}
+	if player == nil {
+		return nil, nil, ErrInvalidReconnect
+	}
+
+	// 이전 연결이 아직 살아 있으면 닫음 (해당 핸들러는 Detach에 실패하고 종료)
+	if old := player.Attach(conn); old != nil && old != conn {
+		old.Close()
+	}
+	player.Disconnected = false
+	player.LastActive = time.Now()
+
+	sendToOthers(room, player.Index, model.OpponentReconnectedMsg{
+		Type:        protocol.TypeOpponentReconnected,
+		PlayerIndex: player.Index,
+	})
+
+	opponentOnline := false
+	for _, p := range room.Players {
+		if p != nil && p != player && !p.Disconnected {
+			opponentOnline = true
+		}
+	}
+
+	// 현재 상태 전송
+	msg := model.RejoinedMsg{
+		Type:           protocol.TypeRejoined,
+		RoomSnapshot:   roomSnapshot(room),
+		PlayerIndex:    player.Index,
+		OpponentOnline: opponentOnline,
+	}
+	player.SendJSON(msg)
+
+	return room, player, nil
+}
+
+// roomSnapshot captures the current room state. Caller must hold room.Mu.
+func roomSnapshot(room *model.Room) model.RoomSnapshot {
+	snapshot := model.RoomSnapshot{
+		RoomCode:   room.Code,
+		State:      room.State,
+		Capacity:   room.Capacity,
+		Host:       room.Host,
+		Rules:      room.Rules,
+		Nicknames:  make([]string, len(room.Players)),
+		Scores:     make([]int, len(room.Players)),
+		TimeLeft:   room.Duration,
+		Spectators: len(room.Spectators),
+	}
+	if room.Rules.BestOf > 1 {
+		snapshot.Round = room.Round
+		snapshot.RoundWins = room.RoundWins
+	}
+	for i, p := range room.Players {
+		if p != nil {
+			snapshot.Nicknames[i] = p.Nickname
+			snapshot.Scores[i] = p.Score
+		}
+	}
+	if room.State == model.StatePlaying {
+		snapshot.TimeLeft = room.Duration - time.Since(room.GameStart).Seconds()
+		for _, ball := range room.Balls {
+			snapshot.Balls = append(snapshot.Balls, model.BallSpawnMsg{
+				Type:      protocol.TypeBallSpawn,
+				ID:        ball.ID,
+				X:         ball.X,
+				Y:         ball.Y,
+				IsRed:     ball.IsRed,
+				Size:      ball.Size,
+				TimeLimit: ball.TimeLimit - time.Since(ball.SpawnedAt).Seconds(),
+			})
+		}
+	}
+	return snapshot
+}
+
+// Spectate attaches a read-only connection to a room
+func (rm *RoomManager) Spectate(code string, spectator *model.Player) (*model.Room, error) {
+	room := rm.GetRoom(code)
+	if room == nil {
+		return nil, ErrRoomNotFound
+	}
+
+	room.Mu.Lock()
+	defer room.Mu.Unlock()
+
+	if len(room.Spectators) >= rm.MaxSpectators {
+		return nil, ErrSpectatorsFull
+	}
+
+	spectator.Index = -1
+	spectator.LastActive = time.Now()
+	room.Spectators = append(room.Spectators, spectator)
+
+	spectator.SendJSON(model.SpectatingMsg{Type: protocol.TypeSpectating, RoomSnapshot: roomSnapshot(room)})
+	broadcast(room, model.SpectatorCountMsg{Type: protocol.TypeSpectatorCount, Count: len(room.Spectators)})
+	rm.notifyLobby(room)
+	return room, nil
+}
+
+// RemoveSpectator detaches a spectator from a room
+func (rm *RoomManager) RemoveSpectator(room *model.Room, spectator *model.Player) {
+	room.Mu.Lock()
+	defer room.Mu.Unlock()
+
+	for i, s := range room.Spectators {
+		if s == spectator {
+			room.Spectators = append(room.Spectators[:i], room.Spectators[i+1:]...)
+			broadcast(room, model.SpectatorCountMsg{Type: protocol.TypeSpectatorCount, Count: len(room.Spectators)})
+			rm.notifyLobby(room)
+			return
+		}
+	}
+}
+
+// broadcast sends a message to all players and spectators.
+// Caller must hold room.Mu.
+func broadcast(room *model.Room, msg interface{}) {
+	for _, p := range room.Players {
+		if p != nil {
+			p.SendJSON(msg)
+		}
+	}
+	sendToSpectators(room, msg)
+}
+
+// sendToOthers sends a message to every player except the given slot and to
+// all spectators. Caller must hold room.Mu.
+func sendToOthers(room *model.Room, playerIndex int, msg interface{}) {
+	for i, p := range room.Players {
+		if p != nil && i != playerIndex {
+			p.SendJSON(msg)
+		}
+	}
+	sendToSpectators(room, msg)
+}
+
+// sendToSpectators sends a message to all spectators. Caller must hold room.Mu.
+func sendToSpectators(room *model.Room, msg interface{}) {
+	for _, s := range room.Spectators {
+		s.SendJSON(msg)
+	}
+}
+
+// HostStart starts the game on the host's request
+func (rm *RoomManager) HostStart(room *model.Room, playerIndex int) error {
+	room.Mu.RLock()
+	host, state, count := room.Host, room.State, playerCount(room)
+	room.Mu.RUnlock()
+
+	if host != playerIndex {
+		return ErrNotHost
+	}
+	if state != model.StateWaiting {
+		return ErrRoomNotAvailable
+	}
+	if count < MinRoomCapacity {
+		return ErrNotEnoughPlayers
+	}
+
+	go rm.StartGame(room)
+	return nil
 }
 
 // StartGame starts the game for a room
 func (rm *RoomManager) StartGame(room *model.Room) {
 	room.Mu.Lock()
-	if room.State != model.StateWaiting || room.Players[0] == nil || room.Players[1] == nil {
+	if room.State != model.StateWaiting || playerCount(room) < MinRoomCapacity {
 		room.Mu.Unlock()
 		return
 	}
+
+	// 새 시리즈 시작 (단판도 1라운드 시리즈로 취급)
+	room.Round = 0
+	room.RoundWins = make([]int, len(room.Players))
+	room.SeriesDuration = 0
+	room.SeriesBalls = 0
 	room.State = model.StateCountdown
+
+	nicknames := make([]string, len(room.Players))
+	for i, p := range room.Players {
+		if p != nil {
+			nicknames[i] = p.Nickname
+			p.CheatScore = 0 // 부정 점수는 게임마다 새로 계산
+		}
+	}
+	room.Replay = model.NewBattleReplay(room.Code, nicknames, room.Rules)
+	rm.notifyLobby(room)
 	room.Mu.Unlock()
 
+	rm.runRound(room)
+}
+
+// runRound counts down and starts the next round. The room must already be
+// in the countdown state.
+func (rm *RoomManager) runRound(room *model.Room) {
 	// Countdown
 	for i := 3; i > 0; i-- {
-		msg := model.CountdownMsg{Type: "countdown", Count: i}
-		room.Players[0].SendJSON(msg)
-		room.Players[1].SendJSON(msg)
-		
+		msg := model.CountdownMsg{Type: protocol.TypeCountdown, Count: i}
+		room.Mu.RLock()
+		broadcast(room, msg)
+		room.Mu.RUnlock()
+
 		select {
 		case <-room.StopGame:
 			return
@@ -189,14 +575,25 @@ func (rm *RoomManager) StartGame(room *model.Room) {
 	room.Mu.Lock()
 	room.State = model.StatePlaying
 	room.GameStart = time.Now()
-	room.Players[0].Score = 0
-	room.Players[1].Score = 0
+	room.Round++
+	for _, p := range room.Players {
+		if p != nil {
+			p.Score = 0
+		}
+	}
 	room.BallCounter = 0
+	room.Replay.Record(model.BattleEventRound, room.Round)
+	rm.notifyLobby(room)
 	room.Mu.Unlock()
 
-	startMsg := model.GameStartMsg{Type: "game_start", Duration: room.Duration}
-	room.Players[0].SendJSON(startMsg)
-	room.Players[1].SendJSON(startMsg)
+	startMsg := model.GameStartMsg{Type: protocol.TypeGameStart, Duration: room.Duration}
+	room.Mu.RLock()
+	if room.Rules.BestOf > 1 {
+		startMsg.Round = room.Round
+		startMsg.BestOf = room.Rules.BestOf
+	}
+	broadcast(room, startMsg)
+	room.Mu.RUnlock()
 
 	// Game loop
 	go rm.gameLoop(room)
@@ -210,7 +607,9 @@ func (rm *RoomManager) gameLoop(room *model.Room) {
 	timeTicker := time.NewTicker(100 * time.Millisecond)
 	defer timeTicker.Stop()
 
-	spawnBall(room)
+	for i := 0; i < room.Rules.Balls; i++ {
+		spawnBall(room)
+	}
 
 	for {
 		select {
@@ -227,56 +626,59 @@ func (rm *RoomManager) gameLoop(room *model.Room) {
 				return
 			}
 
-			timeMsg := model.TimeUpdateMsg{Type: "time_update", TimeLeft: timeLeft}
+			timeMsg := model.TimeUpdateMsg{Type: protocol.TypeTimeUpdate, TimeLeft: timeLeft}
 			room.Mu.RLock()
-			if room.Players[0] != nil {
-				room.Players[0].SendJSON(timeMsg)
-			}
-			if room.Players[1] != nil {
-				room.Players[1].SendJSON(timeMsg)
-			}
+			room.Replay.Record(model.BattleEventTime, model.ReplayMillis(timeLeft))
+			broadcast(room, timeMsg)
 			room.Mu.RUnlock()
 
 		case <-ticker.C:
+			// 만료된 공 처리 (아무도 클릭하지 않음)
 			room.Mu.Lock()
-			if room.CurrentBall != nil && !room.CurrentBall.Clicked {
-				// Check if ball time expired
-				ballElapsed := time.Since(room.CurrentBall.SpawnedAt).Seconds()
-				if ballElapsed >= room.CurrentBall.TimeLimit {
-					// Ball expired, no one clicked
-					sendBallResult(room, -1)
-					room.CurrentBall = nil
-					room.Mu.Unlock()
-					
-					// Spawn new ball after delay
-					time.Sleep(200 * time.Millisecond)
-					spawnBall(room)
+			live := room.Balls[:0]
+			for _, ball := range room.Balls {
+				// 클릭이 접수된 공은 판정(resolveBall)을 기다림
+				if len(ball.Claims) == 0 && time.Since(ball.SpawnedAt).Seconds() >= ball.TimeLimit {
+					sendBallResult(room, ball, -1)
+					go respawnBall(room)
 					continue
 				}
-			} else if room.CurrentBall == nil || room.CurrentBall.Clicked {
-				room.Mu.Unlock()
-				continue
+				live = append(live, ball)
 			}
+			room.Balls = live
 			room.Mu.Unlock()
 		}
 	}
 }
 
-// spawnBall spawns a new ball
+// respawnBall replaces a resolved ball after a short delay
+func respawnBall(room *model.Room) {
+	time.Sleep(200 * time.Millisecond)
+	spawnBall(room)
+}
+
+// spawnBall spawns a new ball if fewer than the room's ball count are live
 func spawnBall(room *model.Room) {
 	room.Mu.Lock()
 	defer room.Mu.Unlock()
 
-	if room.State != model.StatePlaying {
+	if room.State != model.StatePlaying || len(room.Balls) >= room.Rules.Balls {
 		return
 	}
 
 	elapsed := time.Since(room.GameStart).Seconds()
-	config := model.GetGameConfig(elapsed)
+	config := room.Rules.GameConfig(elapsed)
 
+	// 살아 있는 공과 겹치지 않는 위치를 몇 번 시도
 	padding := float64(config.BallSize)
-	x := padding + rand.Float64()*(GameWidth-padding*2)
-	y := padding + rand.Float64()*(GameHeight-padding*2)
+	var x, y float64
+	for attempt := 0; attempt < 10; attempt++ {
+		x = padding + rand.Float64()*(GameWidth-padding*2)
+		y = padding + rand.Float64()*(GameHeight-padding*2)
+		if !overlapsBall(room.Balls, x, y, config.BallSize) {
+			break
+		}
+	}
 	isRed := rand.Float64() > config.BlueChance
 
 	room.BallCounter++
@@ -291,10 +693,12 @@ func spawnBall(room *model.Room) {
 		Clicked:   false,
 		ClickedBy: -1,
 	}
-	room.CurrentBall = ball
+	room.Balls = append(room.Balls, ball)
+	room.Replay.Record(model.BattleEventSpawn, ball.ID, model.ReplayCoord(ball.X), model.ReplayCoord(ball.Y),
+		ball.IsRed, ball.Size, model.ReplayMillis(ball.TimeLimit))
 
 	msg := model.BallSpawnMsg{
-		Type:      "ball_spawn",
+		Type:      protocol.TypeBallSpawn,
 		ID:        ball.ID,
 		X:         ball.X,
 		Y:         ball.Y,
@@ -303,124 +707,371 @@ func spawnBall(room *model.Room) {
 		TimeLimit: ball.TimeLimit,
 	}
 
-	if room.Players[0] != nil {
-		room.Players[0].SendJSON(msg)
-	}
-	if room.Players[1] != nil {
-		room.Players[1].SendJSON(msg)
+	broadcast(room, msg)
+}
+
+// overlapsBall reports whether a ball at (x, y) would overlap a live ball
+func overlapsBall(balls []*model.Ball, x, y float64, size int) bool {
+	for _, ball := range balls {
+		minDistance := float64(ball.Size+size) / 2
+		if math.Hypot(ball.X-x, ball.Y-y) < minDistance {
+			return true
+		}
 	}
+	return false
 }
 
-// HandleClick handles a player clicking the ball
-func (rm *RoomManager) HandleClick(room *model.Room, playerIndex int) {
+// HandleClick handles a player clicking a ball. The click must name a live
+// ball and land on it; rejected clicks count toward the player's cheat score.
+// Valid clicks are collected as claims and the ball goes to the earliest
+// latency-compensated claim after a short arbitration window.
+func (rm *RoomManager) HandleClick(room *model.Room, playerIndex int, click Click) error {
 	room.Mu.Lock()
 	defer room.Mu.Unlock()
 
-	if room.State != model.StatePlaying || room.CurrentBall == nil || room.CurrentBall.Clicked {
-		return
+	if room.State != model.StatePlaying {
+		return nil
+	}
+	if playerIndex < 0 || playerIndex >= len(room.Players) || room.Players[playerIndex] == nil {
+		return nil
+	}
+	player := room.Players[playerIndex]
+	if player.CheatScore >= CheatScoreLimit {
+		recordClick(room, player, playerIndex, click, -1, ErrClickBlocked)
+		return ErrClickBlocked
 	}
 
-	// First click wins
-	room.CurrentBall.Clicked = true
-	room.CurrentBall.ClickedBy = playerIndex
+	if click.BallID <= 0 || click.X == nil || click.Y == nil {
+		flagClick(room, player, ErrInvalidClick)
+		recordClick(room, player, playerIndex, click, -1, ErrInvalidClick)
+		return ErrInvalidClick
+	}
+	if click.BallID > room.BallCounter {
+		flagClick(room, player, ErrInvalidClick)
+		recordClick(room, player, playerIndex, click, -1, ErrInvalidClick)
+		return ErrInvalidClick
+	}
 
-	// Calculate score
-	if room.CurrentBall.IsRed {
-		room.Players[playerIndex].Score++
-	} else {
-		room.Players[playerIndex].Score--
+	var ball *model.Ball
+	for _, b := range room.Balls {
+		if b.ID == click.BallID {
+			ball = b
+			break
+		}
+	}
+	if ball == nil {
+		return nil // 이미 처리된 공 (상대가 먼저 클릭)
+	}
+	for _, claim := range ball.Claims {
+		if claim.PlayerIndex == playerIndex {
+			return nil // 중복 클릭
+		}
 	}
 
-	sendBallResult(room, playerIndex)
-	room.CurrentBall = nil
+	reaction, serverReaction := compensatedReaction(ball, click, player.RTT())
+	err := validateClick(ball, click, serverReaction)
+	recordClick(room, player, playerIndex, click, reaction, err)
+	if err != nil {
+		flagClick(room, player, err)
+		return err
+	}
 
-	// Spawn new ball after short delay (in separate goroutine)
-	go func() {
-		time.Sleep(200 * time.Millisecond)
-		spawnBall(room)
-	}()
+	ball.Claims = append(ball.Claims, model.ClickClaim{PlayerIndex: playerIndex, Reaction: reaction})
+	if len(ball.Claims) == 1 {
+		time.AfterFunc(arbitrationWindow(room), func() {
+			rm.resolveBall(room, ball)
+		})
+	}
+	return nil
 }
 
-// sendBallResult sends ball result to both players
-func sendBallResult(room *model.Room, clickedBy int) {
+// sendBallResult sends a ball's result to all players
+func sendBallResult(room *model.Room, ball *model.Ball, clickedBy int) {
 	clickedByStr := "none"
-	if clickedBy == 0 {
-		clickedByStr = "player1"
-	} else if clickedBy == 1 {
-		clickedByStr = "player2"
+	if clickedBy >= 0 {
+		clickedByStr = fmt.Sprintf("player%d", clickedBy+1)
 	}
 
-	ballID := 0
-	if room.CurrentBall != nil {
-		ballID = room.CurrentBall.ID
+	scores := make([]int, len(room.Players))
+	for i, p := range room.Players {
+		if p != nil {
+			scores[i] = p.Score
+		}
 	}
 
+	room.Replay.Record(model.BattleEventResult, ball.ID, clickedBy, scores)
+
 	msg := model.BallResultMsg{
-		Type:      "ball_result",
-		BallID:    ballID,
+		Type:      protocol.TypeBallResult,
+		BallID:    ball.ID,
 		ClickedBy: clickedByStr,
-		Scores:    [2]int{room.Players[0].Score, room.Players[1].Score},
+		Scores:    scores,
+		Slot:      clickedBy,
+	}
+
+	broadcast(room, msg)
+}
+
+// standings ranks the present players by round score. Caller must hold room.Mu.
+func standings(room *model.Room) []model.Standing {
+	var result []model.Standing
+	for i, p := range room.Players {
+		if p != nil {
+			result = append(result, model.Standing{PlayerIndex: i, Nickname: p.Nickname, Score: p.Score})
+		}
+	}
+	return rank(result)
+}
+
+// seriesStandings ranks the present players by round wins. Caller must hold room.Mu.
+func seriesStandings(room *model.Room) []model.Standing {
+	var result []model.Standing
+	for i, p := range room.Players {
+		if p != nil {
+			result = append(result, model.Standing{PlayerIndex: i, Nickname: p.Nickname, Score: room.RoundWins[i]})
+		}
 	}
+	return rank(result)
+}
+
+// rank sorts standings by score and assigns places (ties share a place)
+func rank(result []model.Standing) []model.Standing {
+	sort.SliceStable(result, func(a, b int) bool {
+		return result[a].Score > result[b].Score
+	})
+	for i := range result {
+		if i > 0 && result[i].Score == result[i-1].Score {
+			result[i].Place = result[i-1].Place
+		} else {
+			result[i].Place = i + 1
+		}
+	}
+	return result
+}
+
+// endRound records a finished round of a series and either schedules the
+// next round or reports that the series is over. Caller must hold room.Mu.
+func (rm *RoomManager) endRound(room *model.Room, ranked []model.Standing) bool {
+	room.SeriesDuration += room.GameEnd.Sub(room.GameStart).Seconds()
+	room.SeriesBalls += room.BallCounter
+
+	// 단독 1위만 라운드 승리
+	winner := -1
+	if len(ranked) > 0 && (len(ranked) == 1 || ranked[1].Place != 1) {
+		winner = ranked[0].PlayerIndex
+		room.RoundWins[winner]++
+	}
+
+	needed := room.Rules.BestOf/2 + 1
+	over := room.Round >= room.Rules.BestOf || (winner >= 0 && room.RoundWins[winner] >= needed)
+
+	msg := model.RoundEndMsg{
+		Type:        protocol.TypeRoundEnd,
+		Round:       room.Round,
+		BestOf:      room.Rules.BestOf,
+		RoundWinner: winner,
+		Standings:   ranked,
+		RoundWins:   room.RoundWins,
+	}
+	if !over {
+		msg.NextRoundIn = SeriesIntermission.Seconds()
+		room.State = model.StateIntermission
+		go rm.nextRound(room, room.StopGame)
+		rm.notifyLobby(room)
+	}
+	broadcast(room, msg)
+
+	return over
+}
 
-	if room.Players[0] != nil {
-		room.Players[0].SendJSON(msg)
+// nextRound starts the next round of a series after the intermission
+func (rm *RoomManager) nextRound(room *model.Room, stop chan struct{}) {
+	select {
+	case <-stop:
+		return
+	case <-time.After(SeriesIntermission):
 	}
-	if room.Players[1] != nil {
-		room.Players[1].SendJSON(msg)
+
+	room.Mu.Lock()
+	if room.State != model.StateIntermission || playerCount(room) < MinRoomCapacity {
+		room.Mu.Unlock()
+		return
 	}
+	room.State = model.StateCountdown
+	rm.notifyLobby(room)
+	room.Mu.Unlock()
+
+	rm.runRound(room)
 }
 
-// endGame ends the game and sends results
+// endGame ends the round and, once the game (or series) is over, sends results
 func (rm *RoomManager) endGame(room *model.Room) {
 	room.Mu.Lock()
-	room.State = model.StateFinished
 	room.GameEnd = time.Now()
-	room.CurrentBall = nil
+	room.Balls = nil
+
+	roundScores := make([]int, len(room.Players))
+	for i, p := range room.Players {
+		if p != nil {
+			roundScores[i] = p.Score
+		}
+	}
+	room.Replay.Record(model.BattleEventRoundEnd, room.Round, roundScores)
+
+	ranked := standings(room)
+	series := room.Rules.BestOf > 1
+	if series {
+		if !rm.endRound(room, ranked) {
+			room.Mu.Unlock()
+			return
+		}
+		ranked = seriesStandings(room)
+	} else {
+		room.SeriesDuration = room.GameEnd.Sub(room.GameStart).Seconds()
+		room.SeriesBalls = room.BallCounter
+	}
+	room.State = model.StateFinished
+	rm.notifyLobby(room)
+
+	for _, p := range room.Players {
+		if p != nil {
+			p.Ready = false
+		}
+	}
 
-	score0 := room.Players[0].Score
-	score1 := room.Players[1].Score
-	nick0 := room.Players[0].Nickname
-	nick1 := room.Players[1].Nickname
+	players := make([]*model.Player, len(room.Players))
+	copy(players, room.Players)
 
-	room.Players[0].Ready = false
-	room.Players[1].Ready = false
+	// 기록과 레이팅은 사람끼리의 1:1 방만 반영
+	var match *model.BattleMatch
+	var matchSlots [2]int
+	if room.Capacity == 2 && len(ranked) == 2 && !hasBot(room) {
+		first, second := ranked[0], ranked[1]
+		if first.PlayerIndex > second.PlayerIndex {
+			first, second = second, first
+		}
+		matchSlots = [2]int{first.PlayerIndex, second.PlayerIndex}
+		match = &model.BattleMatch{
+			RoomCode:  room.Code,
+			Nicknames: [2]string{first.Nickname, second.Nickname},
+			Scores:    [2]int{first.Score, second.Score},
+			Rounds:    room.Round,
+			Duration:  room.SeriesDuration,
+			BallCount: room.SeriesBalls,
+		}
+	}
+	replay := room.Replay
+	roundWins, rounds, bestOf := room.RoundWins, room.Round, room.Rules.BestOf
+	tournamentID, tournamentMatch := room.TournamentID, room.TournamentMatch
 	room.Mu.Unlock()
 
-	// Determine results
-	var result0, result1 string
-	var winnerNickname string
-
-	if score0 > score1 {
-		result0 = "win"
-		result1 = "lose"
-		winnerNickname = nick0
-	} else if score1 > score0 {
-		result0 = "lose"
-		result1 = "win"
-		winnerNickname = nick1
-	} else {
-		result0 = "draw"
-		result1 = "draw"
-		winnerNickname = ""
-	}
-
-	// Send results to player 0
-	room.Players[0].SendJSON(model.GameEndMsg{
-		Type:           "game_end",
-		MyScore:        score0,
-		OpponentScore:  score1,
-		Result:         result0,
-		WinnerNickname: winnerNickname,
-	})
+	if tournamentID != 0 {
+		rm.reportTournamentResult(tournamentID, tournamentMatch, ranked)
+	}
 
-	// Send results to player 1
-	room.Players[1].SendJSON(model.GameEndMsg{
-		Type:           "game_end",
-		MyScore:        score1,
-		OpponentScore:  score0,
-		Result:         result1,
-		WinnerNickname: winnerNickname,
-	})
+	// 대전 기록 및 레이팅 반영 (슬롯 순서 기준)
+	ratings := make(map[int]float64)
+	deltas := make(map[int]float64)
+	var matchID int64
+	if match != nil {
+		if err := RecordBattleMatch(match); err != nil {
+			log.Printf("Failed to record battle match for room %s: %v", room.Code, err)
+		} else {
+			matchID = match.ID
+			for i, index := range matchSlots {
+				ratings[index] = match.RatingsAfter[i]
+				deltas[index] = match.RatingsAfter[i] - match.RatingsBefore[i]
+			}
+		}
+	}
+
+	// 리플레이는 봇전과 다인원 방을 포함해 모든 대전을 저장
+	var replayID int64
+	if replay != nil {
+		var err error
+		if replayID, err = SaveBattleReplay(replay, matchID); err != nil {
+			log.Printf("Failed to save battle replay for room %s: %v", room.Code, err)
+		}
+	}
+
+	// Determine results (단독 1위만 승리, 공동 1위는 무승부)
+	winnerNickname := ""
+	topTied := len(ranked) > 1 && ranked[1].Place == 1
+	if len(ranked) > 0 && !topTied {
+		winnerNickname = ranked[0].Nickname
+	}
+
+	for _, standing := range ranked {
+		result := "lose"
+		if standing.Place == 1 {
+			result = "win"
+			if topTied {
+				result = "draw"
+			}
+		}
+
+		// 상대 점수는 나를 제외한 최고 점수
+		opponentScore := 0
+		for _, other := range ranked {
+			if other.PlayerIndex != standing.PlayerIndex {
+				opponentScore = other.Score
+				break
+			}
+		}
+
+		if series {
+			players[standing.PlayerIndex].SendJSON(model.SeriesEndMsg{
+				Type:           protocol.TypeSeriesEnd,
+				BestOf:         bestOf,
+				Rounds:         rounds,
+				RoundWins:      roundWins,
+				Standings:      ranked,
+				Placement:      standing.Place,
+				Result:         result,
+				WinnerNickname: winnerNickname,
+				Rating:         ratings[standing.PlayerIndex],
+				RatingDelta:    deltas[standing.PlayerIndex],
+				MatchID:        matchID,
+				ReplayID:       replayID,
+			})
+			continue
+		}
+
+		players[standing.PlayerIndex].SendJSON(model.GameEndMsg{
+			Type:           protocol.TypeGameEnd,
+			MyScore:        standing.Score,
+			OpponentScore:  opponentScore,
+			Result:         result,
+			WinnerNickname: winnerNickname,
+			Rating:         ratings[standing.PlayerIndex],
+			RatingDelta:    deltas[standing.PlayerIndex],
+			Placement:      standing.Place,
+			Standings:      ranked,
+			MatchID:        matchID,
+			ReplayID:       replayID,
+		})
+	}
+
+	room.Mu.RLock()
+	if series {
+		sendToSpectators(room, model.SeriesEndMsg{
+			Type:           protocol.TypeSeriesEnd,
+			BestOf:         bestOf,
+			Rounds:         rounds,
+			RoundWins:      roundWins,
+			Standings:      ranked,
+			WinnerNickname: winnerNickname,
+			ReplayID:       replayID,
+		})
+	} else {
+		sendToSpectators(room, model.SpectatorGameEndMsg{
+			Type:           protocol.TypeGameEnd,
+			Standings:      ranked,
+			WinnerNickname: winnerNickname,
+			ReplayID:       replayID,
+		})
+	}
+	room.Mu.RUnlock()
 }
 
 // HandleRematchReady handles rematch ready request
@@ -428,30 +1079,43 @@ func (rm *RoomManager) HandleRematchReady(room *model.Room, playerIndex int) {
 	room.Mu.Lock()
 	defer room.Mu.Unlock()
 
-	if room.State != model.StateFinished {
+	// 토너먼트 경기는 재대결 없음
+	if room.State != model.StateFinished || room.TournamentID != 0 {
+		return
+	}
+	if playerIndex < 0 || playerIndex >= len(room.Players) || room.Players[playerIndex] == nil {
 		return
 	}
 
 	room.Players[playerIndex].Ready = true
 
-	// Notify other player
-	otherIndex := 1 - playerIndex
-	if room.Players[otherIndex] != nil {
-		room.Players[otherIndex].SendJSON(model.OpponentReadyMsg{Type: "opponent_ready"})
-	}
+	// Notify other players
+	sendToOthers(room, playerIndex, model.OpponentReadyMsg{Type: protocol.TypeOpponentReady, PlayerIndex: playerIndex})
 
-	// Check if both are ready
-	if room.Players[0].Ready && room.Players[1].Ready {
-		room.State = model.StateWaiting
-		room.StopGame = make(chan struct{})
-		
-		// Send rematch start
-		room.Players[0].SendJSON(model.RematchStartMsg{Type: "rematch_start"})
-		room.Players[1].SendJSON(model.RematchStartMsg{Type: "rematch_start"})
+	rm.startRematchIfReady(room)
+}
 
-		// Start new game
-		go rm.StartGame(room)
+// startRematchIfReady restarts the game once every remaining player is
+// ready. Caller must hold room.Mu.
+func (rm *RoomManager) startRematchIfReady(room *model.Room) {
+	if playerCount(room) < MinRoomCapacity {
+		return
 	}
+	for _, p := range room.Players {
+		if p != nil && !p.Ready {
+			return
+		}
+	}
+
+	room.State = model.StateWaiting
+	room.StopGame = make(chan struct{})
+	rm.notifyLobby(room)
+
+	// Send rematch start
+	broadcast(room, model.RematchStartMsg{Type: protocol.TypeRematchStart})
+
+	// Start new game
+	go rm.StartGame(room)
 }
 
 // cleanupRoutine periodically cleans up expired rooms
@@ -466,17 +1130,17 @@ func (rm *RoomManager) cleanupRoutine() {
 
 		for code, room := range rm.rooms {
 			room.Mu.RLock()
-			
+
 			// Delete waiting rooms after timeout
 			if room.State == model.StateWaiting && now.Sub(room.CreatedAt) > RoomTimeout {
 				toDelete = append(toDelete, code)
 			}
-			
+
 			// Delete finished rooms after post-game timeout
 			if room.State == model.StateFinished && now.Sub(room.GameEnd) > PostGameTimeout {
 				toDelete = append(toDelete, code)
 			}
-			
+
 			room.Mu.RUnlock()
 		}
 
@@ -486,25 +1150,29 @@ func (rm *RoomManager) cleanupRoutine() {
 				room.Mu.RLock()
 				for _, p := range room.Players {
 					if p != nil {
-						p.SendJSON(model.ErrorMsg{Type: "error", Message: "방이 시간 초과로 삭제되었습니다."})
+						p.SendJSON(model.ErrorMsg{Type: protocol.TypeError, Code: protocol.CodeRoomExpired, Message: "방이 시간 초과로 삭제되었습니다."})
 					}
 				}
+				sendToSpectators(room, model.RoomClosedMsg{Type: protocol.TypeRoomClosed})
 				room.Mu.RUnlock()
-				
+
 				select {
 				case <-room.StopGame:
 				default:
 					close(room.StopGame)
 				}
 				delete(rm.rooms, code)
+				rm.unlistRoom(code)
 			}
 		}
+		rm.pruneLobby()
 		rm.mu.Unlock()
 	}
 }
 
 // Custom errors
 type RoomError struct {
+	Code    protocol.ErrorCode
 	Message string
 }
 
@@ -512,8 +1180,18 @@ func (e RoomError) Error() string {
 	return e.Message
 }
 
+func (e RoomError) ErrorCode() protocol.ErrorCode {
+	return e.Code
+}
+
 var (
-	ErrRoomNotFound    = RoomError{"방을 찾을 수 없습니다"}
-	ErrRoomFull        = RoomError{"방이 가득 찼습니다"}
-	ErrRoomNotAvailable = RoomError{"참가할 수 없는 방입니다"}
+	ErrRoomNotFound     = RoomError{protocol.CodeRoomNotFound, "방을 찾을 수 없습니다"}
+	ErrRoomFull         = RoomError{protocol.CodeRoomFull, "방이 가득 찼습니다"}
+	ErrRoomNotAvailable = RoomError{protocol.CodeRoomNotAvailable, "참가할 수 없는 방입니다"}
+	ErrInvalidReconnect = RoomError{protocol.CodeInvalidReconnect, "재접속할 수 없습니다"}
+	ErrSpectatorsFull   = RoomError{protocol.CodeSpectatorsFull, "관전 인원이 가득 찼습니다"}
+	ErrAlreadyQueued    = RoomError{protocol.CodeAlreadyQueued, "이미 매칭 대기 중입니다"}
+	ErrNotHost          = RoomError{protocol.CodeNotHost, "방장만 시작할 수 있습니다"}
+	ErrNotEnoughPlayers = RoomError{protocol.CodeNotEnoughPlayers, "플레이어가 2명 이상 필요합니다"}
+	ErrInvalidCapacity  = RoomError{protocol.CodeInvalidCapacity, "인원은 2~8명이어야 합니다"}
 )
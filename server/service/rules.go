@@ -0,0 +1,110 @@
+package service
+
+import (
+	"encoding/json"
+
+	"mini-games/model"
+	"mini-games/protocol"
+)
+
+// 방 설정 허용 범위
+const (
+	MinRoundDuration = 5.0
+	MaxRoundDuration = 60.0
+	MaxBluePenalty   = 5
+	MaxBalls         = 5
+	MaxBestOf        = 9
+	MaxCurveSteps    = 8
+)
+
+var (
+	ErrInvalidRules      = RoomError{protocol.CodeInvalidRules, "잘못된 방 설정입니다"}
+	ErrInvalidDuration   = RoomError{protocol.CodeInvalidDuration, "게임 시간은 5~60초여야 합니다"}
+	ErrInvalidDifficulty = RoomError{protocol.CodeInvalidDifficulty, "알 수 없는 난이도입니다"}
+	ErrInvalidCurve      = RoomError{protocol.CodeInvalidCurve, "잘못된 난이도 곡선입니다"}
+	ErrInvalidPenalty    = RoomError{protocol.CodeInvalidPenalty, "파란 공 감점은 0~5점이어야 합니다"}
+	ErrInvalidBalls      = RoomError{protocol.CodeInvalidBalls, "동시 공 개수는 1~5개여야 합니다"}
+	ErrInvalidBestOf     = RoomError{protocol.CodeInvalidBestOf, "라운드 수는 1~9 사이의 홀수여야 합니다"}
+)
+
+// DefaultRoomRules returns the classic 10-second, single-ball, single-round rules
+func DefaultRoomRules() model.RoomRules {
+	return model.RoomRules{
+		Duration:    GameDuration,
+		Difficulty:  model.DifficultyNormal,
+		BluePenalty: 1,
+		Balls:       1,
+		BestOf:      1,
+	}
+}
+
+// ParseRoomRules decodes room options from a create message on top of the
+// defaults and validates them
+func ParseRoomRules(raw json.RawMessage) (model.RoomRules, error) {
+	rules := DefaultRoomRules()
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &rules); err != nil {
+			return rules, ErrInvalidRules
+		}
+	}
+	return rules, ValidateRoomRules(rules)
+}
+
+// ValidateRoomRules checks that room options are within the allowed ranges
+func ValidateRoomRules(rules model.RoomRules) error {
+	if rules.Duration < MinRoundDuration || rules.Duration > MaxRoundDuration {
+		return ErrInvalidDuration
+	}
+
+	switch rules.Difficulty {
+	case model.DifficultyEasy, model.DifficultyNormal, model.DifficultyHard:
+		if len(rules.Curve) > 0 {
+			return ErrInvalidCurve
+		}
+	case model.DifficultyCustom:
+		if err := validateCurve(rules.Curve); err != nil {
+			return err
+		}
+	default:
+		return ErrInvalidDifficulty
+	}
+
+	if rules.BluePenalty < 0 || rules.BluePenalty > MaxBluePenalty {
+		return ErrInvalidPenalty
+	}
+	if rules.Balls < 1 || rules.Balls > MaxBalls {
+		return ErrInvalidBalls
+	}
+	if rules.BestOf < 1 || rules.BestOf > MaxBestOf || rules.BestOf%2 == 0 {
+		return ErrInvalidBestOf
+	}
+	return nil
+}
+
+// validateCurve checks a custom difficulty curve: ascending segment ends,
+// only the last segment may be open-ended, and sane ball parameters
+func validateCurve(curve []model.DifficultyStep) error {
+	if len(curve) == 0 || len(curve) > MaxCurveSteps {
+		return ErrInvalidCurve
+	}
+
+	prev := 0.0
+	for i, step := range curve {
+		last := i == len(curve)-1
+		if step.Until < 0 || (step.Until == 0 && !last) || (step.Until > 0 && step.Until <= prev) {
+			return ErrInvalidCurve
+		}
+		prev = step.Until
+
+		if step.BallSize < 20 || step.BallSize > 150 {
+			return ErrInvalidCurve
+		}
+		if step.TimeLimit < 0.2 || step.TimeLimit > 3 {
+			return ErrInvalidCurve
+		}
+		if step.BlueChance < 0 || step.BlueChance > 0.9 {
+			return ErrInvalidCurve
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"testing"
+
+	"mini-games/model"
+)
+
+func TestValidateRoomRules(t *testing.T) {
+	step := func(until float64) model.DifficultyStep {
+		return model.DifficultyStep{Until: until, BallSize: 60, TimeLimit: 1, BlueChance: 0.2}
+	}
+	rules := func(edit func(r *model.RoomRules)) model.RoomRules {
+		r := DefaultRoomRules()
+		edit(&r)
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		rules   model.RoomRules
+		wantErr error
+	}{
+		{"defaults", DefaultRoomRules(), nil},
+		{"shortest round", rules(func(r *model.RoomRules) { r.Duration = MinRoundDuration }), nil},
+		{"longest round", rules(func(r *model.RoomRules) { r.Duration = MaxRoundDuration }), nil},
+		{"round too short", rules(func(r *model.RoomRules) { r.Duration = MinRoundDuration - 0.1 }), ErrInvalidDuration},
+		{"round too long", rules(func(r *model.RoomRules) { r.Duration = MaxRoundDuration + 1 }), ErrInvalidDuration},
+
+		{"easy", rules(func(r *model.RoomRules) { r.Difficulty = model.DifficultyEasy }), nil},
+		{"hard", rules(func(r *model.RoomRules) { r.Difficulty = model.DifficultyHard }), nil},
+		{"unknown difficulty", rules(func(r *model.RoomRules) { r.Difficulty = "nightmare" }), ErrInvalidDifficulty},
+		{"preset with a curve", rules(func(r *model.RoomRules) { r.Curve = []model.DifficultyStep{step(0)} }), ErrInvalidCurve},
+
+		{"custom curve", rules(func(r *model.RoomRules) {
+			r.Difficulty = model.DifficultyCustom
+			r.Curve = []model.DifficultyStep{step(3), step(6), step(0)}
+		}), nil},
+		{"custom without a curve", rules(func(r *model.RoomRules) { r.Difficulty = model.DifficultyCustom }), ErrInvalidCurve},
+		{"curve too long", rules(func(r *model.RoomRules) {
+			r.Difficulty = model.DifficultyCustom
+			for i := 1; i <= MaxCurveSteps+1; i++ {
+				r.Curve = append(r.Curve, step(float64(i)))
+			}
+		}), ErrInvalidCurve},
+		{"curve not ascending", rules(func(r *model.RoomRules) {
+			r.Difficulty = model.DifficultyCustom
+			r.Curve = []model.DifficultyStep{step(5), step(5)}
+		}), ErrInvalidCurve},
+		{"open-ended step before the last", rules(func(r *model.RoomRules) {
+			r.Difficulty = model.DifficultyCustom
+			r.Curve = []model.DifficultyStep{step(0), step(5)}
+		}), ErrInvalidCurve},
+		{"ball too small", rules(func(r *model.RoomRules) {
+			r.Difficulty = model.DifficultyCustom
+			r.Curve = []model.DifficultyStep{{BallSize: 10, TimeLimit: 1}}
+		}), ErrInvalidCurve},
+		{"ball time too short", rules(func(r *model.RoomRules) {
+			r.Difficulty = model.DifficultyCustom
+			r.Curve = []model.DifficultyStep{{BallSize: 60, TimeLimit: 0.1}}
+		}), ErrInvalidCurve},
+		{"blue chance too high", rules(func(r *model.RoomRules) {
+			r.Difficulty = model.DifficultyCustom
+			r.Curve = []model.DifficultyStep{{BallSize: 60, TimeLimit: 1, BlueChance: 0.95}}
+		}), ErrInvalidCurve},
+
+		{"no blue penalty", rules(func(r *model.RoomRules) { r.BluePenalty = 0 }), nil},
+		{"negative blue penalty", rules(func(r *model.RoomRules) { r.BluePenalty = -1 }), ErrInvalidPenalty},
+		{"blue penalty too high", rules(func(r *model.RoomRules) { r.BluePenalty = MaxBluePenalty + 1 }), ErrInvalidPenalty},
+
+		{"most balls", rules(func(r *model.RoomRules) { r.Balls = MaxBalls }), nil},
+		{"no balls", rules(func(r *model.RoomRules) { r.Balls = 0 }), ErrInvalidBalls},
+		{"too many balls", rules(func(r *model.RoomRules) { r.Balls = MaxBalls + 1 }), ErrInvalidBalls},
+
+		{"best of 9", rules(func(r *model.RoomRules) { r.BestOf = MaxBestOf }), nil},
+		{"best of 0", rules(func(r *model.RoomRules) { r.BestOf = 0 }), ErrInvalidBestOf},
+		{"even best of", rules(func(r *model.RoomRules) { r.BestOf = 4 }), ErrInvalidBestOf},
+		{"best of too many", rules(func(r *model.RoomRules) { r.BestOf = MaxBestOf + 2 }), ErrInvalidBestOf},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateRoomRules(tt.rules); err != tt.wantErr {
+				t.Errorf("ValidateRoomRules() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"mini-games/database"
+	"mini-games/model"
+)
+
+const (
+	// 세션 유지 시간
+	SESSION_TTL = 10 * time.Minute
+
+	// 만료 세션 정리 주기
+	SESSION_SWEEP_INTERVAL = 1 * time.Minute
+)
+
+// SessionStore keeps game sessions for every stateful game. Stateless games
+// (SpeedClick) carry their sessions in signed tokens and never touch it; the
+// required SESSION_SECRET keeps those tokens valid across restarts.
+type SessionStore interface {
+	// Put registers a new session
+	Put(session *model.GameSession) error
+	// Update runs fn on a session and persists the result if fn succeeds
+	Update(sessionID string, fn func(session *model.GameSession) error) error
+	// AppendEvent records an applied event for the session
+	AppendEvent(sessionID string, event string, payload json.RawMessage) error
+	// Delete removes a session
+	Delete(sessionID string) error
+	// Sweep removes sessions started before the cutoff
+	Sweep(before time.Time) (int, error)
+}
+
+// 전체 게임이 공유하는 세션 저장소
+var sessionStore SessionStore = NewMemorySessionStore()
+
+// InitSessionStore selects the session store ("memory" or "sqlite") and
+// starts the expiry sweeper. Must be called after database.Init.
+func InitSessionStore(kind string) {
+	switch kind {
+	case "sqlite":
+		sessionStore = NewSQLiteSessionStore()
+	default:
+		sessionStore = NewMemorySessionStore()
+	}
+	log.Printf("Session store: %s", kind)
+
+	go sweepSessions()
+}
+
+// sweepSessions periodically removes expired sessions and submission records
+func sweepSessions() {
+	ticker := time.NewTicker(SESSION_SWEEP_INTERVAL)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		cutoff := time.Now().Add(-SESSION_TTL)
+
+		if n, err := sessionStore.Sweep(cutoff); err != nil {
+			log.Printf("Session sweep failed: %v", err)
+		} else if n > 0 {
+			log.Printf("Swept %d expired sessions", n)
+		}
+
+		// 만료된 토큰은 재제출이 불가능하므로 제출 기록도 정리
+		if _, err := database.DB.Exec(
+			"DELETE FROM submitted_sessions WHERE created_at < ?",
+			cutoff.UTC().Format("2006-01-02 15:04:05"),
+		); err != nil {
+			log.Printf("Submission sweep failed: %v", err)
+		}
+	}
+}
+
+// MemorySessionStore keeps sessions in process memory
+type MemorySessionStore struct {
+	sessions map[string]*model.GameSession
+	mu       sync.Mutex
+}
+
+// NewMemorySessionStore creates an empty in-memory session store
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{
+		sessions: make(map[string]*model.GameSession),
+	}
+}
+
+// Put registers a session
+func (s *MemorySessionStore) Put(session *model.GameSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.ID] = session
+	return nil
+}
+
+// Update runs fn on a copy of a session while holding the store lock and
+// keeps the copy only if fn succeeds
+func (s *MemorySessionStore) Update(sessionID string, fn func(session *model.GameSession) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, exists := s.sessions[sessionID]
+	if !exists {
+		return ErrSessionNotFound
+	}
+
+	// State는 포인터이므로 저장 형식을 거쳐 깊은 복사
+	working := *session
+	state, err := encodeSessionState(session)
+	if err != nil {
+		return err
+	}
+	if err := decodeSessionState(&working, state); err != nil {
+		return err
+	}
+
+	if err := fn(&working); err != nil {
+		return err
+	}
+	s.sessions[sessionID] = &working
+	return nil
+}
+
+// AppendEvent is a no-op; in-memory sessions keep no event history
+func (s *MemorySessionStore) AppendEvent(sessionID string, event string, payload json.RawMessage) error {
+	return nil
+}
+
+// Delete removes a session
+func (s *MemorySessionStore) Delete(sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sessionID)
+	return nil
+}
+
+// Sweep removes sessions started before the cutoff
+func (s *MemorySessionStore) Sweep(before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for id, session := range s.sessions {
+		if session.StartTime.Before(before) {
+			delete(s.sessions, id)
+			count++
+		}
+	}
+	return count, nil
+}
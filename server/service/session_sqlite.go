@@ -0,0 +1,158 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"mini-games/database"
+	"mini-games/model"
+)
+
+// SQLiteSessionStore keeps sessions in the game_sessions table so
+// in-progress games survive a restart
+type SQLiteSessionStore struct {
+	mu sync.Mutex // 프로세스 내 Update 직렬화
+}
+
+// NewSQLiteSessionStore creates a SQLite-backed session store
+func NewSQLiteSessionStore() *SQLiteSessionStore {
+	return &SQLiteSessionStore{}
+}
+
+// encodeSessionState serializes session.State for storage
+func encodeSessionState(session *model.GameSession) (sql.NullString, error) {
+	if session.State == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(session.State)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// decodeSessionState restores session.State using the game's state type
+func decodeSessionState(session *model.GameSession, state sql.NullString) error {
+	if !state.Valid {
+		return nil
+	}
+	game, ok := GetGame(session.Game)
+	if !ok {
+		return ErrUnknownGame
+	}
+	stateful, ok := game.(StatefulGame)
+	if !ok {
+		return nil
+	}
+	value := stateful.NewState()
+	if err := json.Unmarshal([]byte(state.String), value); err != nil {
+		return err
+	}
+	session.State = value
+	return nil
+}
+
+// Put registers a session
+func (s *SQLiteSessionStore) Put(session *model.GameSession) error {
+	state, err := encodeSessionState(session)
+	if err != nil {
+		return err
+	}
+
+	_, err = database.DB.Exec(
+		`INSERT INTO game_sessions (id, game, seed, level, start_time, score, status, state)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		session.ID, session.Game, session.Seed, session.Level,
+		session.StartTime.UnixMilli(), session.Score, session.Status, state,
+	)
+	return err
+}
+
+// Update loads a session, runs fn and writes it back in one transaction
+func (s *SQLiteSessionStore) Update(sessionID string, fn func(session *model.GameSession) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var session model.GameSession
+	var startMs int64
+	var state sql.NullString
+	err = tx.QueryRow(
+		`SELECT id, game, seed, level, start_time, score, status, state
+		 FROM game_sessions WHERE id = ?`,
+		sessionID,
+	).Scan(&session.ID, &session.Game, &session.Seed, &session.Level, &startMs, &session.Score, &session.Status, &state)
+	if err == sql.ErrNoRows {
+		return ErrSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+	session.StartTime = time.UnixMilli(startMs)
+
+	if err := decodeSessionState(&session, state); err != nil {
+		return err
+	}
+
+	if err := fn(&session); err != nil {
+		return err
+	}
+
+	newState, err := encodeSessionState(&session)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(
+		`UPDATE game_sessions SET level = ?, score = ?, status = ?, state = ?, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = ?`,
+		session.Level, session.Score, session.Status, newState, session.ID,
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// AppendEvent records an applied event for the session
+func (s *SQLiteSessionStore) AppendEvent(sessionID string, event string, payload json.RawMessage) error {
+	_, err := database.DB.Exec(
+		"INSERT INTO session_events (session_id, event, payload) VALUES (?, ?, ?)",
+		sessionID, event, string(payload),
+	)
+	return err
+}
+
+// Delete removes a session and its events
+func (s *SQLiteSessionStore) Delete(sessionID string) error {
+	if _, err := database.DB.Exec("DELETE FROM session_events WHERE session_id = ?", sessionID); err != nil {
+		return err
+	}
+	_, err := database.DB.Exec("DELETE FROM game_sessions WHERE id = ?", sessionID)
+	return err
+}
+
+// Sweep removes sessions started before the cutoff
+func (s *SQLiteSessionStore) Sweep(before time.Time) (int, error) {
+	cutoff := before.UnixMilli()
+
+	if _, err := database.DB.Exec(
+		"DELETE FROM session_events WHERE session_id IN (SELECT id FROM game_sessions WHERE start_time < ?)",
+		cutoff,
+	); err != nil {
+		return 0, err
+	}
+
+	result, err := database.DB.Exec("DELETE FROM game_sessions WHERE start_time < ?", cutoff)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
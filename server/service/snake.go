@@ -0,0 +1,243 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"mini-games/model"
+)
+
+const (
+	SNAKE_GRID_SIZE  = 20
+	SNAKE_FOOD_SCORE = 10
+
+	// 시뮬레이션 최대 틱 수
+	SNAKE_MAX_TICKS = 100000
+
+	// 플레이 시간 허용 오차 (ms)
+	SNAKE_TIME_TOLERANCE = 500
+)
+
+// Snake 레벨 설정 (클라이언트와 동일해야 함)
+var snakeLevels = map[string]model.SnakeLevel{
+	"easy":   {Speed: 200, SpeedIncrease: 0, MinSpeed: 200},
+	"medium": {Speed: 150, SpeedIncrease: 5, MinSpeed: 80},
+	"hard":   {Speed: 100, SpeedIncrease: 5, MinSpeed: 50},
+}
+
+type snakePoint struct {
+	X, Y int
+}
+
+var snakeDirections = map[string]snakePoint{
+	"U": {0, -1},
+	"D": {0, 1},
+	"L": {-1, 0},
+	"R": {1, 0},
+}
+
+var snakeOpposite = map[string]string{
+	"U": "D",
+	"D": "U",
+	"L": "R",
+	"R": "L",
+}
+
+var (
+	ErrInvalidLevel     = errors.New("Invalid level")
+	ErrInvalidDirection = errors.New("Invalid direction")
+	ErrInvalidMoveOrder = errors.New("Moves must be in increasing tick order")
+	ErrMovesAfterEnd    = errors.New("Move log continues after game over")
+	ErrTooManyTicks     = errors.New("Game too long")
+)
+
+// IsValidSnakeLevel reports whether the level exists
+func IsValidSnakeLevel(level string) bool {
+	_, ok := snakeLevels[level]
+	return ok
+}
+
+// generateSnakeFood picks a free cell using the seeded PRNG
+func generateSnakeFood(rng *Mulberry32, snake []snakePoint) snakePoint {
+	for {
+		food := snakePoint{
+			X: int(rng.Next() * SNAKE_GRID_SIZE),
+			Y: int(rng.Next() * SNAKE_GRID_SIZE),
+		}
+		occupied := false
+		for _, segment := range snake {
+			if segment == food {
+				occupied = true
+				break
+			}
+		}
+		if !occupied {
+			return food
+		}
+	}
+}
+
+// SimulateSnake replays a move log and returns the verified result
+func SimulateSnake(seed int64, level string, moves []model.SnakeMove) (model.SnakeResult, error) {
+	config, ok := snakeLevels[level]
+	if !ok {
+		return model.SnakeResult{}, ErrInvalidLevel
+	}
+
+	// 이동 기록 검증
+	for i, move := range moves {
+		if _, ok := snakeDirections[move.Dir]; !ok {
+			return model.SnakeResult{}, ErrInvalidDirection
+		}
+		if move.Tick < 0 || (i > 0 && move.Tick <= moves[i-1].Tick) {
+			return model.SnakeResult{}, ErrInvalidMoveOrder
+		}
+	}
+
+	rng := NewMulberry32(seed)
+	snake := []snakePoint{{10, 10}}
+	food := snakePoint{15, 10} // 첫 음식은 고정 위치
+	dir := "R"
+	speed := config.Speed
+	score := 0
+	var duration int64
+	nextMove := 0
+
+	for tick := 0; ; tick++ {
+		if tick >= SNAKE_MAX_TICKS {
+			return model.SnakeResult{}, ErrTooManyTicks
+		}
+
+		// 이 틱에 예약된 방향 전환 적용
+		if nextMove < len(moves) && moves[nextMove].Tick == tick {
+			if moves[nextMove].Dir != snakeOpposite[dir] {
+				dir = moves[nextMove].Dir
+			}
+			nextMove++
+		}
+
+		duration += int64(speed)
+
+		d := snakeDirections[dir]
+		head := snakePoint{snake[0].X + d.X, snake[0].Y + d.Y}
+
+		// 벽 또는 자기 몸 충돌
+		collided := head.X < 0 || head.X >= SNAKE_GRID_SIZE || head.Y < 0 || head.Y >= SNAKE_GRID_SIZE
+		for _, segment := range snake {
+			if collided {
+				break
+			}
+			collided = segment == head
+		}
+		if collided {
+			if nextMove < len(moves) {
+				return model.SnakeResult{}, ErrMovesAfterEnd
+			}
+			return model.SnakeResult{
+				Score:      score,
+				Length:     len(snake),
+				Ticks:      tick + 1,
+				DurationMs: duration,
+			}, nil
+		}
+
+		snake = append([]snakePoint{head}, snake...)
+
+		if head == food {
+			score += SNAKE_FOOD_SCORE
+			if config.SpeedIncrease > 0 {
+				speed -= config.SpeedIncrease
+				if speed < config.MinSpeed {
+					speed = config.MinSpeed
+				}
+			}
+			// 판이 가득 차면 게임 종료
+			if len(snake) == SNAKE_GRID_SIZE*SNAKE_GRID_SIZE {
+				if nextMove < len(moves) {
+					return model.SnakeResult{}, ErrMovesAfterEnd
+				}
+				return model.SnakeResult{
+					Score:      score,
+					Length:     len(snake),
+					Ticks:      tick + 1,
+					DurationMs: duration,
+				}, nil
+			}
+			food = generateSnakeFood(rng, snake)
+		} else {
+			snake = snake[:len(snake)-1]
+		}
+	}
+}
+
+// snakeGame implements Game for Snake
+type snakeGame struct{}
+
+func init() {
+	RegisterGame("snake", snakeGame{})
+}
+
+// Start records the selected level
+func (snakeGame) Start(session *model.GameSession, params json.RawMessage) (interface{}, error) {
+	var req model.SnakeStartRequest
+	if err := decodeParams(params, &req); err != nil {
+		return nil, err
+	}
+	if !IsValidSnakeLevel(req.Level) {
+		return nil, ErrInvalidLevel
+	}
+	session.Level = req.Level
+
+	return model.SnakeStartResponse{
+		SessionID: session.ID,
+		Seed:      session.Seed,
+		StartTime: session.StartTime.UnixMilli(),
+		Level:     session.Level,
+	}, nil
+}
+
+// Apply has no in-game events; the whole move log is sent on finish
+func (snakeGame) Apply(session *model.GameSession, event string, payload json.RawMessage) (interface{}, error) {
+	return nil, ErrUnknownEvent
+}
+
+// Finish verifies the move log and ends the session
+func (snakeGame) Finish(session *model.GameSession, payload json.RawMessage) (interface{}, error) {
+	var req model.SnakeFinishRequest
+	if err := decodeParams(payload, &req); err != nil {
+		return nil, err
+	}
+
+	if session.Status != "playing" {
+		return model.SnakeFinishResponse{Message: "Game not in progress"}, nil
+	}
+
+	result, err := SimulateSnake(session.Seed, session.Level, req.Moves)
+	if err != nil {
+		session.Status = "rejected"
+		return model.SnakeFinishResponse{Message: err.Error()}, nil
+	}
+
+	// 시뮬레이션 시간보다 빨리 끝난 게임은 거부
+	elapsed := time.Since(session.StartTime).Milliseconds()
+	if elapsed+SNAKE_TIME_TOLERANCE < result.DurationMs {
+		session.Status = "rejected"
+		return model.SnakeFinishResponse{Message: "Game finished too quickly"}, nil
+	}
+
+	session.Score = result.Score
+	session.Status = "ended"
+
+	return model.SnakeFinishResponse{
+		FinalScore: result.Score,
+		Length:     result.Length,
+		Ticks:      result.Ticks,
+		CanSubmit:  result.Score > 0,
+	}, nil
+}
+
+// Score returns the simulated score
+func (snakeGame) Score(session *model.GameSession) int {
+	return session.Score
+}
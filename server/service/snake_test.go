@@ -0,0 +1,96 @@
+package service
+
+import (
+	"testing"
+
+	"mini-games/model"
+)
+
+func TestSimulateSnake(t *testing.T) {
+	tests := []struct {
+		name    string
+		seed    int64
+		level   string
+		moves   []model.SnakeMove
+		want    model.SnakeResult
+		wantErr error
+	}{
+		{
+			// (10,10)에서 오른쪽으로: 4틱에 첫 음식 (15,10), 9틱에 벽
+			name:  "straight into the wall eats the first food",
+			seed:  1,
+			level: "easy",
+			want:  model.SnakeResult{Score: 10, Length: 2, Ticks: 10, DurationMs: 2000},
+		},
+		{
+			// 음식을 먹은 뒤 속도가 150 → 145
+			name:  "medium speeds up after eating",
+			seed:  1,
+			level: "medium",
+			want:  model.SnakeResult{Score: 10, Length: 2, Ticks: 10, DurationMs: 5*150 + 5*145},
+		},
+		{
+			name:  "turning up at the first tick",
+			seed:  1,
+			level: "easy",
+			moves: []model.SnakeMove{{Tick: 0, Dir: "U"}},
+			want:  model.SnakeResult{Score: 0, Length: 1, Ticks: 11, DurationMs: 2200},
+		},
+		{
+			name:  "reversing is ignored",
+			seed:  1,
+			level: "easy",
+			moves: []model.SnakeMove{{Tick: 0, Dir: "L"}},
+			want:  model.SnakeResult{Score: 10, Length: 2, Ticks: 10, DurationMs: 2000},
+		},
+		{
+			// 오른쪽 벽 앞에서 아래로 꺾어 바닥 벽까지
+			name:  "turning before the wall",
+			seed:  1,
+			level: "hard",
+			moves: []model.SnakeMove{{Tick: 9, Dir: "D"}},
+			want:  model.SnakeResult{Score: 10, Length: 2, Ticks: 19, DurationMs: 5*100 + 14*95},
+		},
+		{name: "unknown level", seed: 1, level: "insane", wantErr: ErrInvalidLevel},
+		{
+			name:    "unknown direction",
+			seed:    1,
+			level:   "easy",
+			moves:   []model.SnakeMove{{Tick: 0, Dir: "X"}},
+			wantErr: ErrInvalidDirection,
+		},
+		{
+			name:    "ticks out of order",
+			seed:    1,
+			level:   "easy",
+			moves:   []model.SnakeMove{{Tick: 3, Dir: "U"}, {Tick: 3, Dir: "L"}},
+			wantErr: ErrInvalidMoveOrder,
+		},
+		{
+			name:    "negative tick",
+			seed:    1,
+			level:   "easy",
+			moves:   []model.SnakeMove{{Tick: -1, Dir: "U"}},
+			wantErr: ErrInvalidMoveOrder,
+		},
+		{
+			name:    "moves after hitting the wall",
+			seed:    1,
+			level:   "easy",
+			moves:   []model.SnakeMove{{Tick: 50, Dir: "U"}},
+			wantErr: ErrMovesAfterEnd,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SimulateSnake(tt.seed, tt.level, tt.moves)
+			if err != tt.wantErr {
+				t.Fatalf("SimulateSnake() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("SimulateSnake() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
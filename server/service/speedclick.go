@@ -2,8 +2,9 @@ package service
 
 import (
 	"crypto/rand"
+	"encoding/binary"
 	"encoding/hex"
-	"sync"
+	"encoding/json"
 	"time"
 
 	"mini-games/model"
@@ -16,6 +17,8 @@ const (
 
 	// 네트워크 지연 허용 범위 (ms)
 	CLICK_TIME_TOLERANCE = 200
+
+	SPEEDCLICK_LIVES = 3
 )
 
 // SpeedClick 레벨 설정 (클라이언트와 동일해야 함)
@@ -29,12 +32,6 @@ var speedClickLevels = []model.SpeedClickLevel{
 	{Level: 7, TimeLimit: 0.40, BallSize: 50, BlueChance: 0.30, RequiredScore: 30},
 }
 
-// 세션 저장소
-var (
-	sessions   = make(map[string]*model.GameSession)
-	sessionsMu sync.RWMutex
-)
-
 // Mulberry32 PRNG state
 type Mulberry32 struct {
 	state uint32
@@ -61,6 +58,15 @@ func generateSessionID() string {
 	return hex.EncodeToString(bytes)
 }
 
+// generateSeed creates an unpredictable session seed (never derived from
+// the clock, which start responses expose). Seeds stay within 32 bits:
+// Mulberry32 uses no more, and JS clients must read them exactly.
+func generateSeed() int64 {
+	bytes := make([]byte, 4)
+	rand.Read(bytes)
+	return int64(binary.BigEndian.Uint32(bytes))
+}
+
 // getLevelConfig returns the level config for a given score
 func getLevelConfig(score int) model.SpeedClickLevel {
 	for i := len(speedClickLevels) - 1; i >= 0; i-- {
@@ -99,125 +105,234 @@ func GenerateBall(seed int64, index int, score int, prevBallEndTime int64) model
 	}
 }
 
-// CreateSpeedClickSession creates a new game session
-func CreateSpeedClickSession() *model.GameSession {
-	sessionID := generateSessionID()
-	seed := time.Now().UnixNano()
-
-	session := &model.GameSession{
-		ID:            sessionID,
-		Game:          "speed-click",
-		Seed:          seed,
-		StartTime:     time.Now(),
-		Score:         0,
-		Lives:         3,
+// checkClickTime validates click timing and returns a rejection message
+func checkClickTime(ball model.SpeedClickBall, clickTimeMs int64) string {
+	ballEndTime := ball.SpawnTime + ball.Duration
+
+	// 클릭이 너무 빠름 (공 생성 전)
+	if clickTimeMs < ball.SpawnTime-CLICK_TIME_TOLERANCE {
+		return "Click too early"
+	}
+
+	// 클릭이 너무 늦음 (공 만료 후)
+	if clickTimeMs > ballEndTime+CLICK_TIME_TOLERANCE {
+		return "Click too late"
+	}
+	return ""
+}
+
+// clickPoints returns the points for clicking a ball (0 for blue balls)
+func clickPoints(ball model.SpeedClickBall, clickTimeMs int64) int {
+	if !ball.IsRed {
+		return 0
+	}
+
+	// 빨간 공: 점수 획득
+	ballEndTime := ball.SpawnTime + ball.Duration
+	timeRatio := float64(ballEndTime-clickTimeMs) / float64(ball.Duration)
+	if timeRatio < 0 {
+		timeRatio = 0
+	}
+	if timeRatio > 1 {
+		timeRatio = 1
+	}
+
+	points := ball.Level
+	if timeRatio >= 0.75 {
+		points += 2
+	} else if timeRatio >= 0.50 {
+		points += 1
+	}
+	return points
+}
+
+// speedClickGame implements Game for SpeedClick
+type speedClickGame struct{}
+
+func init() {
+	RegisterGame("speed-click", speedClickGame{})
+}
+
+// Start initializes lives and the first ball
+func (speedClickGame) Start(session *model.GameSession, params json.RawMessage) (interface{}, error) {
+	session.State = &model.SpeedClickState{
+		Lives:         SPEEDCLICK_LIVES,
 		CurrentBall:   0,
 		BallSpawnTime: 0, // 첫 공은 즉시 생성
 		Clicks:        []model.ClickRecord{},
-		Status:        "playing",
+		Misses:        []int{},
 	}
 
-	sessionsMu.Lock()
-	sessions[sessionID] = session
-	sessionsMu.Unlock()
+	return model.StartGameResponse{
+		SessionID: session.ID,
+		Seed:      session.Seed,
+		StartTime: session.StartTime.UnixMilli(),
+	}, nil
+}
+
+// Apply handles "click" and "miss" events
+func (speedClickGame) Apply(session *model.GameSession, event string, payload json.RawMessage) (interface{}, error) {
+	switch event {
+	case "click":
+		var req model.ClickRequest
+		if err := decodeParams(payload, &req); err != nil {
+			return nil, err
+		}
+		return processClick(session, req.BallIndex, req.ClickTimeMs), nil
+	case "miss":
+		var req model.MissRequest
+		if err := decodeParams(payload, &req); err != nil {
+			return nil, err
+		}
+		return processMiss(session, req.BallIndex), nil
+	}
+	return nil, ErrUnknownEvent
+}
 
-	// 10분 후 자동 삭제
-	go func() {
-		time.Sleep(10 * time.Minute)
-		sessionsMu.Lock()
-		delete(sessions, sessionID)
-		sessionsMu.Unlock()
-	}()
+// Finish ends the game
+func (speedClickGame) Finish(session *model.GameSession, payload json.RawMessage) (interface{}, error) {
+	if session.Status == "playing" {
+		session.Status = "ended"
+	}
 
-	return session
+	return model.EndGameResponse{
+		FinalScore: session.Score,
+		CanSubmit:  session.Status == "ended" && session.Score > 0,
+	}, nil
 }
 
-// GetSession retrieves a session by ID
-func GetSession(sessionID string) *model.GameSession {
-	sessionsMu.RLock()
-	defer sessionsMu.RUnlock()
-	return sessions[sessionID]
+// Score returns the incrementally validated score
+func (speedClickGame) Score(session *model.GameSession) int {
+	return session.Score
 }
 
-// ProcessClick handles a click event
-func ProcessClick(sessionID string, ballIndex int, clickTimeMs int64) model.ClickResponse {
-	sessionsMu.Lock()
-	defer sessionsMu.Unlock()
+// speedClickTokenState is the compact state carried in a session token
+type speedClickTokenState struct {
+	Lives         int    `json:"l"`
+	CurrentBall   int    `json:"b"`
+	BallSpawnTime int64  `json:"t"`
+	Hash          string `json:"h"`
+}
+
+// EncodeState keeps only the running state; the event log is replaced by its hash
+func (speedClickGame) EncodeState(session *model.GameSession) (json.RawMessage, error) {
+	state := session.State.(*model.SpeedClickState)
+	return json.Marshal(speedClickTokenState{
+		Lives:         state.Lives,
+		CurrentBall:   state.CurrentBall,
+		BallSpawnTime: state.BallSpawnTime,
+		Hash:          state.Hash,
+	})
+}
 
-	session, exists := sessions[sessionID]
-	if !exists {
-		return model.ClickResponse{Valid: false, Message: "Session not found"}
+// DecodeState restores the running state from a token
+func (speedClickGame) DecodeState(session *model.GameSession, raw json.RawMessage) error {
+	var compact speedClickTokenState
+	if err := json.Unmarshal(raw, &compact); err != nil {
+		return err
 	}
+	session.State = &model.SpeedClickState{
+		Lives:         compact.Lives,
+		CurrentBall:   compact.CurrentBall,
+		BallSpawnTime: compact.BallSpawnTime,
+		Hash:          compact.Hash,
+	}
+	return nil
+}
 
+// VerifySubmit checks the submitted event log against the token's hash chain
+// and replays it before the score is saved
+func (speedClickGame) VerifySubmit(session *model.GameSession, payload json.RawMessage) error {
+	var req model.SpeedClickSubmitRequest
+	if err := decodeParams(payload, &req); err != nil {
+		return err
+	}
+
+	state := session.State.(*model.SpeedClickState)
+	replay := model.SpeedClickReplay{
+		Seed:   session.Seed,
+		Clicks: req.Clicks,
+		Misses: req.Misses,
+	}
+
+	hash, err := speedClickReplayHash(replay)
+	if err != nil || hash != state.Hash {
+		return ErrReplayHashMismatch
+	}
+
+	score, err := VerifySpeedClickReplay(session.Seed, replay)
+	if err != nil {
+		return err
+	}
+	if score != session.Score {
+		return ErrReplayScoreMismatch
+	}
+
+	state.Clicks = replay.Clicks
+	state.Misses = replay.Misses
+	return nil
+}
+
+// SaveReplay stores the seed and event log alongside the submitted score
+func (speedClickGame) SaveReplay(scoreID int64, session *model.GameSession) error {
+	state := session.State.(*model.SpeedClickState)
+	return SaveSpeedClickReplay(scoreID, model.SpeedClickReplay{
+		Seed:   session.Seed,
+		Clicks: state.Clicks,
+		Misses: state.Misses,
+	})
+}
+
+// processClick handles a click event
+func processClick(session *model.GameSession, ballIndex int, clickTimeMs int64) model.ClickResponse {
 	if session.Status != "playing" {
 		return model.ClickResponse{Valid: false, Message: "Game not in progress"}
 	}
 
+	state := session.State.(*model.SpeedClickState)
+
 	// 현재 공 인덱스 확인
-	if ballIndex != session.CurrentBall {
+	if ballIndex != state.CurrentBall {
 		return model.ClickResponse{Valid: false, Message: "Invalid ball index"}
 	}
 
-	// 이미 클릭한 공인지 확인
-	for _, click := range session.Clicks {
-		if click.BallIndex == ballIndex {
-			return model.ClickResponse{Valid: false, Message: "Ball already clicked"}
-		}
+	// 아직 일어나지 않은 시간의 클릭은 거부
+	if clickTimeMs > time.Since(session.StartTime).Milliseconds()+CLICK_TIME_TOLERANCE {
+		return model.ClickResponse{Valid: false, Message: "Click from the future"}
 	}
 
 	// 공 생성 (서버 측에서 검증용)
-	ball := GenerateBall(session.Seed, ballIndex, session.Score, session.BallSpawnTime)
+	ball := GenerateBall(session.Seed, ballIndex, session.Score, state.BallSpawnTime)
 
 	// 클릭 시간 검증 (네트워크 지연 허용)
-	ballEndTime := ball.SpawnTime + ball.Duration
-	
-	// 클릭이 너무 빠름 (공 생성 전)
-	if clickTimeMs < ball.SpawnTime-CLICK_TIME_TOLERANCE {
-		return model.ClickResponse{Valid: false, Message: "Click too early"}
-	}
-
-	// 클릭이 너무 늦음 (공 만료 후)
-	if clickTimeMs > ballEndTime+CLICK_TIME_TOLERANCE {
-		return model.ClickResponse{Valid: false, Message: "Click too late"}
+	if message := checkClickTime(ball, clickTimeMs); message != "" {
+		return model.ClickResponse{Valid: false, Message: message}
 	}
+	ballEndTime := ball.SpawnTime + ball.Duration
 
 	// 점수 계산
-	points := 0
+	points := clickPoints(ball, clickTimeMs)
 	if ball.IsRed {
-		// 빨간 공: 점수 획득
-		timeRatio := float64(ballEndTime-clickTimeMs) / float64(ball.Duration)
-		if timeRatio < 0 {
-			timeRatio = 0
-		}
-		if timeRatio > 1 {
-			timeRatio = 1
-		}
-
-		points = ball.Level
-		if timeRatio >= 0.75 {
-			points += 2
-		} else if timeRatio >= 0.50 {
-			points += 1
-		}
 		session.Score += points
 	} else {
 		// 파란 공: 목숨 감소
-		session.Lives--
+		state.Lives--
 	}
 
 	// 클릭 기록
-	session.Clicks = append(session.Clicks, model.ClickRecord{
+	state.Clicks = append(state.Clicks, model.ClickRecord{
 		BallIndex: ballIndex,
 		ClickTime: clickTimeMs,
 		Valid:     true,
 		Points:    points,
 	})
+	state.Hash = chainSpeedClickHash(state.Hash, clickEvent(ballIndex, clickTimeMs))
 
 	// 다음 공으로 이동
-	session.CurrentBall++
-	session.BallSpawnTime = ballEndTime // 이 공이 끝나는 시간 기준
+	state.CurrentBall++
+	state.BallSpawnTime = ballEndTime // 이 공이 끝나는 시간 기준
 
-	gameOver := session.Lives <= 0
+	gameOver := state.Lives <= 0
 	if gameOver {
 		session.Status = "ended"
 	}
@@ -226,106 +341,45 @@ func ProcessClick(sessionID string, ballIndex int, clickTimeMs int64) model.Clic
 		Valid:    true,
 		Points:   points,
 		Score:    session.Score,
-		Lives:    session.Lives,
+		Lives:    state.Lives,
 		GameOver: gameOver,
 	}
 }
 
-// ProcessMiss handles a missed ball (time expired without click)
-func ProcessMiss(sessionID string, ballIndex int) model.MissResponse {
-	sessionsMu.Lock()
-	defer sessionsMu.Unlock()
-
-	session, exists := sessions[sessionID]
-	if !exists {
-		return model.MissResponse{Valid: false}
-	}
-
+// processMiss handles a missed ball (time expired without click)
+func processMiss(session *model.GameSession, ballIndex int) model.MissResponse {
 	if session.Status != "playing" {
 		return model.MissResponse{Valid: false}
 	}
 
-	if ballIndex != session.CurrentBall {
+	state := session.State.(*model.SpeedClickState)
+
+	if ballIndex != state.CurrentBall {
 		return model.MissResponse{Valid: false}
 	}
 
 	// 공 생성 (검증용)
-	ball := GenerateBall(session.Seed, ballIndex, session.Score, session.BallSpawnTime)
+	ball := GenerateBall(session.Seed, ballIndex, session.Score, state.BallSpawnTime)
 
 	// 빨간 공을 놓친 경우만 목숨 감소
 	if ball.IsRed {
-		session.Lives--
+		state.Lives--
 	}
+	state.Misses = append(state.Misses, ballIndex)
+	state.Hash = chainSpeedClickHash(state.Hash, missEvent(ballIndex))
 
 	// 다음 공으로 이동
-	session.CurrentBall++
-	session.BallSpawnTime = ball.SpawnTime + ball.Duration
+	state.CurrentBall++
+	state.BallSpawnTime = ball.SpawnTime + ball.Duration
 
-	gameOver := session.Lives <= 0
+	gameOver := state.Lives <= 0
 	if gameOver {
 		session.Status = "ended"
 	}
 
 	return model.MissResponse{
 		Valid:    true,
-		Lives:    session.Lives,
+		Lives:    state.Lives,
 		GameOver: gameOver,
 	}
 }
-
-// EndSpeedClickSession ends a game session
-func EndSpeedClickSession(sessionID string) model.EndGameResponse {
-	sessionsMu.Lock()
-	defer sessionsMu.Unlock()
-
-	session, exists := sessions[sessionID]
-	if !exists {
-		return model.EndGameResponse{FinalScore: 0, CanSubmit: false}
-	}
-
-	if session.Status == "playing" {
-		session.Status = "ended"
-	}
-
-	return model.EndGameResponse{
-		FinalScore: session.Score,
-		CanSubmit:  session.Status == "ended" && session.Score > 0,
-	}
-}
-
-// SubmitSpeedClickScore saves the score to database
-func SubmitSpeedClickScore(sessionID string, nickname string) model.SubmitScoreResponse {
-	sessionsMu.Lock()
-	session, exists := sessions[sessionID]
-	if !exists {
-		sessionsMu.Unlock()
-		return model.SubmitScoreResponse{Success: false}
-	}
-
-	if session.Status != "ended" {
-		sessionsMu.Unlock()
-		return model.SubmitScoreResponse{Success: false}
-	}
-
-	// 상태를 submitted로 변경 (중복 제출 방지)
-	session.Status = "submitted"
-	score := session.Score
-	sessionsMu.Unlock()
-
-	// DB에 점수 저장
-	input := model.ScoreInput{
-		Nickname: nickname,
-		Game:     "speed-click",
-		Score:    score,
-	}
-
-	scoreID, err := SaveScore(input)
-	if err != nil {
-		return model.SubmitScoreResponse{Success: false}
-	}
-
-	return model.SubmitScoreResponse{
-		Success: true,
-		ScoreID: scoreID,
-	}
-}
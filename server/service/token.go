@@ -0,0 +1,136 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"mini-games/database"
+	"mini-games/model"
+)
+
+var (
+	ErrSessionSecretMissing = errors.New("SESSION_SECRET must be set")
+	ErrInvalidToken         = errors.New("Invalid session token")
+	ErrTokenExpired         = errors.New("Session token expired")
+	ErrAlreadySubmitted     = errors.New("Session already submitted")
+)
+
+// 세션 토큰 서명 키 (재시작과 서버 인스턴스 간에 유지되어야 함)
+var sessionSecret []byte
+
+// InitSessionSecret loads the token signing key from SESSION_SECRET. It is
+// required: a per-boot key would invalidate every in-progress SpeedClick
+// run on restart.
+func InitSessionSecret() error {
+	secret := os.Getenv("SESSION_SECRET")
+	if secret == "" {
+		return ErrSessionSecretMissing
+	}
+	sessionSecret = []byte(secret)
+	return nil
+}
+
+// sessionTokenPayload is the signed content of a session token
+type sessionTokenPayload struct {
+	ID     string          `json:"id"`
+	Game   string          `json:"g"`
+	Seed   int64           `json:"s"`
+	Level  string          `json:"l,omitempty"`
+	Start  int64           `json:"t"` // unix ms
+	Score  int             `json:"sc"`
+	Status string          `json:"st"`
+	State  json.RawMessage `json:"x,omitempty"` // 게임별 상태 (해시 체인 포함)
+}
+
+func signToken(data []byte) []byte {
+	mac := hmac.New(sha256.New, sessionSecret)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// EncodeSessionToken signs a session and its compact game state
+func EncodeSessionToken(session *model.GameSession, state json.RawMessage) (string, error) {
+	data, err := json.Marshal(sessionTokenPayload{
+		ID:     session.ID,
+		Game:   session.Game,
+		Seed:   session.Seed,
+		Level:  session.Level,
+		Start:  session.StartTime.UnixMilli(),
+		Score:  session.Score,
+		Status: session.Status,
+		State:  state,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encoding := base64.RawURLEncoding
+	return encoding.EncodeToString(data) + "." + encoding.EncodeToString(signToken(data)), nil
+}
+
+// DecodeSessionToken verifies a token and restores the session it carries
+func DecodeSessionToken(token string) (*model.GameSession, json.RawMessage, error) {
+	encoding := base64.RawURLEncoding
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, nil, ErrInvalidToken
+	}
+	data, err := encoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, ErrInvalidToken
+	}
+	signature, err := encoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, signToken(data)) {
+		return nil, nil, ErrInvalidToken
+	}
+
+	var payload sessionTokenPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, nil, ErrInvalidToken
+	}
+
+	startTime := time.UnixMilli(payload.Start)
+	if time.Since(startTime) > SESSION_TTL {
+		return nil, nil, ErrTokenExpired
+	}
+
+	session := &model.GameSession{
+		ID:        payload.ID,
+		Game:      payload.Game,
+		Seed:      payload.Seed,
+		Level:     payload.Level,
+		StartTime: startTime,
+		Score:     payload.Score,
+		Status:    payload.Status,
+	}
+	return session, payload.State, nil
+}
+
+// claimSubmission records a stateless session as submitted so the same
+// token cannot be submitted twice on any server instance
+func claimSubmission(session *model.GameSession) error {
+	result, err := database.DB.Exec(
+		"INSERT OR IGNORE INTO submitted_sessions (session_id, game) VALUES (?, ?)",
+		session.ID, session.Game,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrAlreadySubmitted
+	}
+	return nil
+}
+
+// unclaimSubmission removes a claim whose score was never saved
+func unclaimSubmission(session *model.GameSession) error {
+	_, err := database.DB.Exec("DELETE FROM submitted_sessions WHERE session_id = ?", session.ID)
+	return err
+}
@@ -0,0 +1,456 @@
+package service
+
+import (
+	"crypto/subtle"
+	"log"
+	"sync"
+	"time"
+
+	"mini-games/model"
+	"mini-games/protocol"
+)
+
+// 참가자 수 범위
+const (
+	MinTournamentEntrants = 2
+	MaxTournamentEntrants = 64
+)
+
+var (
+	ErrTournamentNotFound = RoomError{protocol.CodeTournamentNotFound, "토너먼트를 찾을 수 없습니다"}
+	ErrTournamentFinished = RoomError{protocol.CodeTournamentFinished, "이미 끝난 토너먼트입니다"}
+	ErrTournamentFormat   = RoomError{protocol.CodeInvalidTournament, "알 수 없는 토너먼트 방식입니다"}
+	ErrTournamentEntrants = RoomError{protocol.CodeInvalidTournament, "참가자는 2~64명이어야 합니다"}
+	ErrDuplicateEntrant   = RoomError{protocol.CodeInvalidTournament, "참가자 닉네임이 중복되었습니다"}
+	ErrNotEntrant         = RoomError{protocol.CodeNotEntrant, "토너먼트 참가자가 아닙니다"}
+	ErrInvalidCheckIn     = RoomError{protocol.CodeInvalidCheckIn, "체크인 토큰이 올바르지 않습니다"}
+	ErrEliminated         = RoomError{protocol.CodeEliminated, "이미 탈락했습니다"}
+	ErrMatchInProgress    = RoomError{protocol.CodeMatchInProgress, "이미 경기가 진행 중입니다"}
+)
+
+// tournament is a running bracket with its check-ins
+type tournament struct {
+	t        *model.Tournament
+	checkIns map[string]*QueueTicket // 닉네임별 체크인
+	tokens   map[string]string       // 닉네임별 체크인 토큰
+	mu       sync.Mutex
+}
+
+// CreateTournament builds a single- or double-elimination bracket for the
+// entrants (in seed order). First-round byes go to the top seeds. The
+// returned copy carries each entrant's check-in token; later copies don't.
+func (rm *RoomManager) CreateTournament(name, format string, entrants []string, rules model.RoomRules) (*model.Tournament, error) {
+	if format != model.TournamentSingle && format != model.TournamentDouble {
+		return nil, ErrTournamentFormat
+	}
+	if len(entrants) < MinTournamentEntrants || len(entrants) > MaxTournamentEntrants {
+		return nil, ErrTournamentEntrants
+	}
+	seen := make(map[string]bool, len(entrants))
+	for _, nickname := range entrants {
+		if seen[nickname] {
+			return nil, ErrDuplicateEntrant
+		}
+		seen[nickname] = true
+	}
+
+	tr := &tournament{
+		t: &model.Tournament{
+			Name:      name,
+			Format:    format,
+			Entrants:  entrants,
+			Rules:     rules,
+			State:     model.TournamentRunning,
+			CreatedAt: time.Now(),
+		},
+		checkIns: make(map[string]*QueueTicket),
+		tokens:   make(map[string]string, len(entrants)),
+	}
+	for _, nickname := range entrants {
+		tr.tokens[nickname] = generateSessionID()
+	}
+	buildBracket(tr.t)
+
+	// 1라운드 부전승 처리
+	for _, m := range tr.t.Matches {
+		if m.Round == 1 && m.Bracket == model.BracketWinners {
+			tr.settle(m)
+		}
+	}
+
+	rm.tournamentsMu.Lock()
+	rm.nextTournamentID++
+	tr.t.ID = rm.nextTournamentID
+	rm.tournaments[tr.t.ID] = tr
+	rm.tournamentsMu.Unlock()
+
+	snapshot, err := rm.GetTournament(tr.t.ID)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.CheckInTokens = tr.tokens
+	return snapshot, nil
+}
+
+// GetTournament returns a copy of a tournament's current bracket
+func (rm *RoomManager) GetTournament(id int64) (*model.Tournament, error) {
+	tr := rm.tournament(id)
+	if tr == nil {
+		return nil, ErrTournamentNotFound
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	snapshot := *tr.t
+	snapshot.Matches = make([]*model.TournamentMatch, len(tr.t.Matches))
+	for i, m := range tr.t.Matches {
+		match := *m
+		snapshot.Matches[i] = &match
+	}
+	return &snapshot, nil
+}
+
+func (rm *RoomManager) tournament(id int64) *tournament {
+	rm.tournamentsMu.Lock()
+	defer rm.tournamentsMu.Unlock()
+	return rm.tournaments[id]
+}
+
+// seedOrder returns the bracket positions of seeds 1..size so that the top
+// seeds meet as late as possible (1, 8, 4, 5, 2, 7, 3, 6 for 8)
+func seedOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		next := make([]int, 0, len(order)*2)
+		for _, seed := range order {
+			next = append(next, seed, len(order)*2+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// buildBracket creates every match of the tournament and links where
+// winners and losers go next
+func buildBracket(t *model.Tournament) {
+	size, rounds := 1, 0
+	for size < len(t.Entrants) {
+		size *= 2
+		rounds++
+	}
+
+	add := func(bracket string, round int) *model.TournamentMatch {
+		m := &model.TournamentMatch{
+			ID:      len(t.Matches) + 1,
+			Bracket: bracket,
+			Round:   round,
+			State:   model.MatchWaiting,
+		}
+		t.Matches = append(t.Matches, m)
+		return m
+	}
+
+	// 승자조: r라운드는 size/2^r 경기
+	winners := make([][]*model.TournamentMatch, rounds+1)
+	for r := 1; r <= rounds; r++ {
+		for i := 0; i < size>>r; i++ {
+			winners[r] = append(winners[r], add(model.BracketWinners, r))
+		}
+	}
+	for r := 1; r < rounds; r++ {
+		for i, m := range winners[r] {
+			m.WinnerTo = &model.TournamentSlot{Match: winners[r+1][i/2].ID, Slot: i % 2}
+		}
+	}
+
+	// 시드 배치 (빈 자리는 부전승)
+	order := seedOrder(size)
+	for i, m := range winners[1] {
+		for slot := 0; slot < 2; slot++ {
+			if seed := order[i*2+slot]; seed <= len(t.Entrants) {
+				m.Players[slot] = t.Entrants[seed-1]
+			}
+			m.Filled[slot] = true
+		}
+	}
+
+	if t.Format == model.TournamentSingle {
+		return
+	}
+
+	// 패자조: 홀수 라운드는 패자조끼리, 짝수 라운드는 승자조 탈락자 합류
+	losersRounds := 2 * (rounds - 1)
+	losers := make([][]*model.TournamentMatch, losersRounds+1)
+	for r := 1; r <= losersRounds; r++ {
+		for i := 0; i < size>>((r+1)/2+1); i++ {
+			losers[r] = append(losers[r], add(model.BracketLosers, r))
+		}
+	}
+	for r := 1; r < losersRounds; r++ {
+		for i, m := range losers[r] {
+			if r%2 == 1 {
+				m.WinnerTo = &model.TournamentSlot{Match: losers[r+1][i].ID, Slot: 0}
+			} else {
+				m.WinnerTo = &model.TournamentSlot{Match: losers[r+1][i/2].ID, Slot: i % 2}
+			}
+		}
+	}
+
+	if rounds > 1 {
+		for i, m := range winners[1] {
+			m.LoserTo = &model.TournamentSlot{Match: losers[1][i/2].ID, Slot: i % 2}
+		}
+	}
+	// 바로 재대결하지 않도록 역순으로 배치
+	for r := 2; r <= rounds; r++ {
+		target := losers[2*(r-1)]
+		for i, m := range winners[r] {
+			m.LoserTo = &model.TournamentSlot{Match: target[len(target)-1-i].ID, Slot: 1}
+		}
+	}
+
+	// 결승 1경기는 승자조 우승자가 0번 슬롯, 리셋 경기는 finish에서 채움
+	final := add(model.BracketFinal, 1)
+	add(model.BracketFinal, 2)
+	winners[rounds][0].WinnerTo = &model.TournamentSlot{Match: final.ID, Slot: 0}
+	if losersRounds > 0 {
+		losers[losersRounds][0].WinnerTo = &model.TournamentSlot{Match: final.ID, Slot: 1}
+	} else {
+		winners[rounds][0].LoserTo = &model.TournamentSlot{Match: final.ID, Slot: 1}
+	}
+}
+
+// place puts a player ("" for nobody) into a later match's slot.
+// Caller must hold tr.mu.
+func (tr *tournament) place(to *model.TournamentSlot, nickname string) {
+	if to == nil {
+		return
+	}
+	m := tr.t.Matches[to.Match-1]
+	m.Players[to.Slot] = nickname
+	m.Filled[to.Slot] = true
+	tr.settle(m)
+}
+
+// settle marks a match ready once both slots are known, or passes a lone
+// player straight through as a bye. Caller must hold tr.mu.
+func (tr *tournament) settle(m *model.TournamentMatch) {
+	if m.State != model.MatchWaiting || !m.Filled[0] || !m.Filled[1] {
+		return
+	}
+	if m.Players[0] != "" && m.Players[1] != "" {
+		m.State = model.MatchReady
+		return
+	}
+
+	m.Bye = true
+	winner := m.Players[0]
+	if winner == "" {
+		winner = m.Players[1]
+	}
+	tr.finish(m, winner)
+}
+
+// finish records a match winner and advances both players.
+// Caller must hold tr.mu.
+func (tr *tournament) finish(m *model.TournamentMatch, winner string) {
+	loser := m.Players[0]
+	if loser == winner {
+		loser = m.Players[1]
+	}
+	m.State = model.MatchDone
+	m.Winner = winner
+
+	// 더블 엘리미네이션 결승: 패자조 우승자가 이기면 둘 다 1패라 리셋 경기
+	if m.Bracket == model.BracketFinal && m.Round == 1 && tr.t.Format == model.TournamentDouble {
+		reset := tr.t.Matches[m.ID]
+		if winner == m.Players[1] {
+			tr.place(&model.TournamentSlot{Match: reset.ID, Slot: 0}, m.Players[0])
+			tr.place(&model.TournamentSlot{Match: reset.ID, Slot: 1}, winner)
+			return
+		}
+		reset.State = model.MatchSkipped
+	}
+
+	if m.WinnerTo == nil {
+		tr.t.Champion = winner
+		tr.t.State = model.TournamentFinished
+		return
+	}
+	tr.place(m.WinnerTo, winner)
+	tr.place(m.LoserTo, loser)
+}
+
+// nextMatch returns the unfinished match a player is placed in, or nil if
+// they are out. Caller must hold tr.mu.
+func (tr *tournament) nextMatch(nickname string) *model.TournamentMatch {
+	for _, m := range tr.t.Matches {
+		if m.State != model.MatchDone && m.State != model.MatchSkipped && (m.Players[0] == nickname || m.Players[1] == nickname) {
+			return m
+		}
+	}
+	return nil
+}
+
+// seed returns a player's seed (0 is the top seed)
+func (tr *tournament) seed(nickname string) int {
+	for i, entrant := range tr.t.Entrants {
+		if entrant == nickname {
+			return i
+		}
+	}
+	return len(tr.t.Entrants)
+}
+
+// CheckIn registers a connected player for their next tournament match,
+// using the check-in token issued to that entrant. The returned ticket
+// resolves to the match room (see TicketRoom) once both players of the
+// pairing have checked in.
+func (rm *RoomManager) CheckIn(id int64, player *model.Player, token string) (*QueueTicket, error) {
+	tr := rm.tournament(id)
+	if tr == nil {
+		return nil, ErrTournamentNotFound
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if tr.t.State == model.TournamentFinished {
+		return nil, ErrTournamentFinished
+	}
+	if tr.seed(player.Nickname) == len(tr.t.Entrants) {
+		return nil, ErrNotEntrant
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(tr.tokens[player.Nickname])) != 1 {
+		return nil, ErrInvalidCheckIn
+	}
+	m := tr.nextMatch(player.Nickname)
+	if m == nil {
+		return nil, ErrEliminated
+	}
+	if m.State == model.MatchPlaying {
+		return nil, ErrMatchInProgress // 끊겼다면 rejoin으로 복귀
+	}
+
+	// 토큰을 가진 본인이 다시 체크인하면 새 연결로 교체
+	ticket := &QueueTicket{Player: player, JoinedAt: time.Now(), tournament: tr}
+	tr.checkIns[player.Nickname] = ticket
+
+	opponent := m.Players[0]
+	if opponent == player.Nickname {
+		opponent = m.Players[1]
+	}
+	player.SendJSON(model.TournamentCheckedInMsg{
+		Type:         protocol.TypeTournamentCheckedIn,
+		TournamentID: id,
+		MatchID:      m.ID,
+		Opponent:     opponent,
+	})
+
+	rm.startTournamentMatches(tr)
+	return ticket, nil
+}
+
+// cancelCheckIn withdraws a tournament check-in that has not been matched
+// into a room yet
+func (rm *RoomManager) cancelCheckIn(ticket *QueueTicket) bool {
+	tr := ticket.tournament
+	if tr == nil {
+		return false
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if tr.checkIns[ticket.Player.Nickname] != ticket {
+		return false
+	}
+	delete(tr.checkIns, ticket.Player.Nickname)
+	return true
+}
+
+// startTournamentMatches opens a room for every ready match whose players
+// have both checked in. Caller must hold tr.mu.
+func (rm *RoomManager) startTournamentMatches(tr *tournament) {
+	for _, m := range tr.t.Matches {
+		if m.State != model.MatchReady {
+			continue
+		}
+		a, b := tr.checkIns[m.Players[0]], tr.checkIns[m.Players[1]]
+		if a == nil || b == nil {
+			continue
+		}
+
+		room, code := rm.CreateRoom(a.Player, 2, tr.t.Rules)
+		room.Mu.Lock()
+		room.TournamentID = tr.t.ID
+		room.TournamentMatch = m.ID
+		room.Mu.Unlock()
+		if _, err := rm.JoinRoom(code, b.Player); err != nil {
+			log.Printf("Tournament %d failed to join room %s: %v", tr.t.ID, code, err)
+			rm.RemoveRoom(code)
+			continue
+		}
+
+		m.State = model.MatchPlaying
+		m.RoomCode = code
+		delete(tr.checkIns, m.Players[0])
+		delete(tr.checkIns, m.Players[1])
+
+		rm.queueMu.Lock()
+		a.room, b.room = room, room
+		rm.queueMu.Unlock()
+
+		for i, ticket := range []*QueueTicket{a, b} {
+			ticket.Player.SendJSON(model.TournamentMatchMsg{
+				Type:           protocol.TypeTournamentMatch,
+				TournamentID:   tr.t.ID,
+				MatchID:        m.ID,
+				RoomCode:       code,
+				PlayerIndex:    i,
+				Opponent:       m.Players[1-i],
+				ReconnectToken: ticket.Player.ReconnectToken,
+			})
+		}
+		go rm.StartGame(room)
+	}
+}
+
+// reportTournamentResult advances the bracket from a finished (or
+// forfeited) match. A tie goes to the higher seed.
+func (rm *RoomManager) reportTournamentResult(id int64, matchID int, ranked []model.Standing) {
+	tr := rm.tournament(id)
+	if tr == nil {
+		return
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if matchID < 1 || matchID > len(tr.t.Matches) {
+		return
+	}
+	m := tr.t.Matches[matchID-1]
+	if m.State != model.MatchPlaying {
+		return // 이미 반영됨
+	}
+
+	winner := ""
+	for _, standing := range ranked {
+		for slot, nickname := range m.Players {
+			if standing.Nickname == nickname {
+				m.Scores[slot] = standing.Score
+			}
+		}
+		if standing.Place == 1 && (winner == "" || tr.seed(standing.Nickname) < tr.seed(winner)) {
+			winner = standing.Nickname
+		}
+	}
+	if winner != m.Players[0] && winner != m.Players[1] {
+		return
+	}
+
+	tr.finish(m, winner)
+	rm.startTournamentMatches(tr)
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"mini-games/model"
+)
+
+func entrantNames(n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("p%d", i+1)
+	}
+	return names
+}
+
+func TestBuildBracket(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		entrants    int
+		wantMatches map[string]int // 대진표별 경기 수
+		wantByes    int
+	}{
+		{"single, 2 entrants", model.TournamentSingle, 2, map[string]int{model.BracketWinners: 1}, 0},
+		{"single, 5 entrants", model.TournamentSingle, 5, map[string]int{model.BracketWinners: 7}, 3},
+		{"single, 8 entrants", model.TournamentSingle, 8, map[string]int{model.BracketWinners: 7}, 0},
+		{"double, 2 entrants", model.TournamentDouble, 2, map[string]int{model.BracketWinners: 1, model.BracketFinal: 2}, 0},
+		{"double, 3 entrants", model.TournamentDouble, 3, map[string]int{model.BracketWinners: 3, model.BracketLosers: 2, model.BracketFinal: 2}, 1},
+		{"double, 4 entrants", model.TournamentDouble, 4, map[string]int{model.BracketWinners: 3, model.BracketLosers: 2, model.BracketFinal: 2}, 0},
+		{"double, 8 entrants", model.TournamentDouble, 8, map[string]int{model.BracketWinners: 7, model.BracketLosers: 6, model.BracketFinal: 2}, 0},
+		{"double, 6 entrants", model.TournamentDouble, 6, map[string]int{model.BracketWinners: 7, model.BracketLosers: 6, model.BracketFinal: 2}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bracket := &model.Tournament{Format: tt.format, Entrants: entrantNames(tt.entrants)}
+			buildBracket(bracket)
+
+			counts := make(map[string]int)
+			byes := 0
+			for i, m := range bracket.Matches {
+				if m.ID != i+1 {
+					t.Fatalf("match %d has ID %d", i+1, m.ID)
+				}
+				counts[m.Bracket]++
+				if m.Bracket == model.BracketWinners && m.Round == 1 && (m.Players[0] == "" || m.Players[1] == "") {
+					byes++
+				}
+			}
+			if fmt.Sprint(counts) != fmt.Sprint(tt.wantMatches) {
+				t.Errorf("matches = %v, want %v", counts, tt.wantMatches)
+			}
+			if byes != tt.wantByes {
+				t.Errorf("byes = %d, want %d", byes, tt.wantByes)
+			}
+
+			// 1라운드와 결승 리셋을 뺀 모든 자리는 정확히 한 경기에서 채워짐
+			fed := make(map[model.TournamentSlot]int)
+			for _, m := range bracket.Matches {
+				for _, to := range []*model.TournamentSlot{m.WinnerTo, m.LoserTo} {
+					if to == nil {
+						continue
+					}
+					if to.Match <= m.ID || to.Match > len(bracket.Matches) {
+						t.Errorf("match %d feeds match %d", m.ID, to.Match)
+					}
+					fed[*to]++
+				}
+			}
+			for _, m := range bracket.Matches {
+				first := m.Bracket == model.BracketWinners && m.Round == 1
+				reset := m.Bracket == model.BracketFinal && m.Round == 2
+				for slot := 0; slot < 2; slot++ {
+					want := 1
+					if first || reset {
+						want = 0
+					}
+					if got := fed[model.TournamentSlot{Match: m.ID, Slot: slot}]; got != want {
+						t.Errorf("match %d (%s %d) slot %d fed %d times, want %d", m.ID, m.Bracket, m.Round, slot, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestTournamentPlayThrough(t *testing.T) {
+	// 시드가 높은 선수가 이김 (결승 경기는 따로 지정)
+	topSeed := func(tr *tournament, m *model.TournamentMatch) string {
+		if tr.seed(m.Players[0]) < tr.seed(m.Players[1]) {
+			return m.Players[0]
+		}
+		return m.Players[1]
+	}
+	finalWinner := func(first, reset int) func(*tournament, *model.TournamentMatch) string {
+		return func(tr *tournament, m *model.TournamentMatch) string {
+			switch {
+			case m.Bracket == model.BracketFinal && m.Round == 1:
+				return m.Players[first]
+			case m.Bracket == model.BracketFinal && m.Round == 2:
+				return m.Players[reset]
+			}
+			return topSeed(tr, m)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		format       string
+		entrants     int
+		pick         func(*tournament, *model.TournamentMatch) string
+		wantChampion string
+		wantReset    string // 결승 리셋 경기 상태 ("": 더블 엘리미네이션 아님)
+	}{
+		{"single, top seed wins", model.TournamentSingle, 8, topSeed, "p1", ""},
+		{"single with byes", model.TournamentSingle, 5, topSeed, "p1", ""},
+		{"double, winners champion takes the final", model.TournamentDouble, 4, topSeed, "p1", model.MatchSkipped},
+		{"double, losers champion forces a reset and wins it", model.TournamentDouble, 4, finalWinner(1, 1), "p2", model.MatchDone},
+		{"double, winners champion wins the reset", model.TournamentDouble, 4, finalWinner(1, 0), "p1", model.MatchDone},
+		{"double with byes, reset", model.TournamentDouble, 6, finalWinner(1, 1), "p2", model.MatchDone},
+		{"double, 2 entrants, reset", model.TournamentDouble, 2, finalWinner(1, 1), "p2", model.MatchDone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &tournament{t: &model.Tournament{
+				Format:   tt.format,
+				Entrants: entrantNames(tt.entrants),
+				State:    model.TournamentRunning,
+			}}
+			buildBracket(tr.t)
+			for _, m := range tr.t.Matches {
+				if m.Round == 1 && m.Bracket == model.BracketWinners {
+					tr.settle(m)
+				}
+			}
+
+			for tr.t.State != model.TournamentFinished {
+				var ready *model.TournamentMatch
+				for _, m := range tr.t.Matches {
+					if m.State == model.MatchReady {
+						ready = m
+						break
+					}
+				}
+				if ready == nil {
+					t.Fatalf("no match ready before the tournament finished")
+				}
+				tr.finish(ready, tt.pick(tr, ready))
+			}
+
+			if tr.t.Champion != tt.wantChampion {
+				t.Errorf("champion = %q, want %q", tr.t.Champion, tt.wantChampion)
+			}
+			for _, m := range tr.t.Matches {
+				if m.Bracket == model.BracketFinal && m.Round == 2 {
+					if m.State != tt.wantReset {
+						t.Errorf("reset match state = %q, want %q", m.State, tt.wantReset)
+					}
+					continue
+				}
+				if m.State != model.MatchDone {
+					t.Errorf("match %d (%s %d) left %q", m.ID, m.Bracket, m.Round, m.State)
+				}
+			}
+		})
+	}
+}